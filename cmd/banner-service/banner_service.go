@@ -1,85 +1,265 @@
 package main
 
 import (
+	"banner-service/internal/auth"
 	"banner-service/internal/config"
 	handlers "banner-service/internal/handlers"
+	"banner-service/internal/logging"
+	"banner-service/internal/metrics"
+	"banner-service/internal/middlewares"
+	auditrepo "banner-service/internal/repositories/audit"
 	bannerrepo "banner-service/internal/repositories/banner"
+	experimentrepo "banner-service/internal/repositories/experiment"
+	featurerepo "banner-service/internal/repositories/feature"
+	jobrepo "banner-service/internal/repositories/job"
+	statsrepo "banner-service/internal/repositories/stats"
+	tagrepo "banner-service/internal/repositories/tag"
+	userrepo "banner-service/internal/repositories/user"
 	bannerservice "banner-service/internal/services"
+	"banner-service/internal/tracing"
+	"context"
+	"flag"
+	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
-	"strconv"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
 )
 
 func main() {
-	redisHost := os.Getenv("REDIS_HOST")
-	redisPort := os.Getenv("REDIS_PORT")
-	if redisPort == "" {
-		redisPort = "6379"
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML configuration file")
+	flag.Parse()
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatalf("Could not load configuration: %v", err)
+	}
+
+	logger := logging.New(cfg.Log, os.Stdout)
+	slog.SetDefault(logger)
+	logger.Info("effective configuration", "config", fmt.Sprintf("%+v", cfg.Redacted()))
+	if cfg.Auth.DevMode {
+		logger.Warn("DEV MODE IS ENABLED: /token and /admin-token issue tokens without authentication, never enable it in production")
 	}
-	redisAddr := redisHost + ":" + redisPort
-	redisPassword := os.Getenv("REDIS_PASSWORD")
-	redisDB, _ := strconv.Atoi(os.Getenv("REDIS_DB"))
-
-	redisCfg := config.RedisConfig{
-		Addr:         redisAddr,
-		Password:     redisPassword,
-		DB:           redisDB,
-		DialTimeout:  5 * time.Second,
-		ReadTimeout:  3 * time.Second,
-		WriteTimeout: 3 * time.Second,
-		PoolSize:     10,
-		MinIdleConns: 2,
-		MaxConnAge:   30 * time.Minute,
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		fatal(logger, "could not set up tracing", err)
 	}
 
-	rdb := config.NewRedisClient(redisCfg)
-	defer rdb.Close()
+	rdb := config.NewRedisClient(cfg.Redis)
+	rdb.AddHook(tracing.RedisHook{})
 
-	postgresHost := os.Getenv("DB_HOST")
-	postgresPort := os.Getenv("DB_PORT")
-	if postgresPort == "" {
-		postgresPort = "5432"
+	pool, err := config.NewPostgresPool(cfg.Postgres)
+	if err != nil {
+		fatal(logger, "could not create Postgres pool", err)
 	}
-	postgresUser := os.Getenv("DB_USER")
-	postgresPassword := os.Getenv("DB_PASS")
-	postgresDB := os.Getenv("DB_NAME")
-	postgresConnStr := "postgresql://" + postgresUser + ":" + postgresPassword + "@" + postgresHost + "/" + postgresDB + "?sslmode=disable"
-
-	postgresCfg := config.PostgresConfig{
-		ConnStr:         postgresConnStr,
-		MaxConns:        25,
-		MaxConnIdleTime: 15 * time.Minute,
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var workers sync.WaitGroup
+
+	tracedPool := tracing.NewPgxPool(pool)
+
+	dbRepo := bannerrepo.NewPostgresBannerRepository(tracedPool, cfg.Banners, logger)
+
+	redisCacheRepo := bannerrepo.NewRedisBannerRepository(rdb, logger)
+
+	cacheTiers := []bannerrepo.CacheTier{{Name: "redis", Cache: redisCacheRepo}}
+
+	var localCacheRepo *bannerrepo.LRUBannerRepository
+	if cfg.Cache.LocalSize > 0 {
+		localCacheRepo = bannerrepo.NewLRUBannerRepository(cfg.Cache.LocalSize, cfg.Cache.LocalTTL, cfg.Cache.LocalJitter)
+		cacheTiers = append([]bannerrepo.CacheTier{{Name: "local", Cache: localCacheRepo}}, cacheTiers...)
 	}
 
-	pool, err := config.NewPostgresPool(postgresCfg)
+	cacheRepo := bannerrepo.NewTieredBannerRepository(cfg.Cache.LocalTTL, logger, cacheTiers...)
+
+	metrics.RegisterCacheCollector(cacheRepo)
+	metrics.RegisterPoolCollector(pool)
+
+	featureRepo := featurerepo.NewPostgresFeatureRepository(tracedPool)
+
+	experimentRepo := experimentrepo.NewPostgresExperimentRepository(tracedPool)
+
+	srv := bannerservice.NewBannerService(cacheRepo, dbRepo, featureRepo, experimentRepo, cfg.Cache, logger)
+
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		redisCacheRepo.SubscribeInvalidations(ctx, func(keys []string) {
+			srv.MarkInvalidated(keys)
+			if localCacheRepo != nil {
+				localCacheRepo.Invalidate(keys)
+			}
+		})
+	}()
+
+	warmupSrv := bannerservice.NewWarmupService(srv, dbRepo, cfg.Cache.WarmupRate, logger)
+
+	jobRepo := jobrepo.NewPostgresJobRepository(tracedPool)
+	jobSrv := bannerservice.NewJobService(jobRepo, dbRepo, srv, warmupSrv, logger)
+
+	auditRepo := auditrepo.NewPostgresAuditRepository(tracedPool)
+	auditSrv := bannerservice.NewAuditService(auditRepo)
+
+	statsRepo := statsrepo.NewPostgresStatsRepository(tracedPool)
+	statsSrv := bannerservice.NewStatsService(statsRepo, cfg.Stats, logger)
+
+	experimentSrv := bannerservice.NewExperimentService(experimentRepo, featureRepo, srv, logger)
+
+	featureSrv := bannerservice.NewFeatureService(featureRepo, dbRepo, srv, logger)
+
+	tagRepo := tagrepo.NewPostgresTagRepository(tracedPool)
+	tagSrv := bannerservice.NewTagService(tagRepo, dbRepo, srv, logger)
+
+	verifier, err := auth.NewVerifier(cfg.Auth)
 	if err != nil {
-		log.Fatalf("Could not create Postgres pool: %v", err)
+		fatal(logger, "could not create JWT verifier", err)
 	}
-	defer pool.Close()
 
-	dbRepo := bannerrepo.NewPostgresBannerRepository(pool)
+	signer, err := auth.NewSigner(cfg.Auth)
+	if err != nil {
+		fatal(logger, "could not create JWT signer", err)
+	}
 
-	cacheRepo := bannerrepo.NewRedisBannerRepository(rdb)
+	userRepo := userrepo.NewPostgresUserRepository(tracedPool)
+	userSrv := bannerservice.NewUserService(userRepo, signer, cfg.Auth)
 
-	srv := bannerservice.NewBannerService(cacheRepo, dbRepo)
+	authMiddleware := middlewares.NewAuthMiddleware(verifier, userSrv)
 
+	if cfg.Auth.AdminLogin != "" {
+		if err := userSrv.EnsureAdmin(ctx, cfg.Auth.AdminLogin, cfg.Auth.AdminPassword); err != nil {
+			fatal(logger, "could not create admin user", err)
+		}
+	}
+
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		jobSrv.Run(ctx)
+	}()
+
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		statsSrv.Run(ctx)
+	}()
+
+	if cfg.Cache.WarmupOnStartup {
+		warmupSrv.Start(ctx)
+	}
+
+	healthSrv := bannerservice.NewHealthService(cfg.HTTP.HealthCheckTimeout,
+		bannerservice.HealthCheck{Name: "postgres", Critical: true, Ping: pool.Ping},
+		bannerservice.HealthCheck{Name: "redis", Ping: func(ctx context.Context) error {
+			return rdb.Ping(ctx).Err()
+		}},
+	)
+
+	accessLogMiddleware := middlewares.NewAccessLogMiddleware(logger)
 	r := mux.NewRouter()
-	handlers.InitBannerRoutes(srv, r)
-	handlers.InitUserRoutes(r)
+	r.Use(middlewares.MetricsMiddleware, otelmux.Middleware(cfg.Tracing.ServiceName, otelmux.WithFilter(tracedRequest)))
+	r.Use(middlewares.RequestIDMiddleware, accessLogMiddleware)
+	r.NotFoundHandler = unmatched(http.NotFoundHandler(), middlewares.MetricsMiddleware, middlewares.RequestIDMiddleware, accessLogMiddleware)
+	r.MethodNotAllowedHandler = unmatched(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}), middlewares.MetricsMiddleware, middlewares.RequestIDMiddleware, accessLogMiddleware)
+	r.Handle("/metrics", promhttp.Handler()).Methods("GET")
+	handlers.InitHealthRoutes(healthSrv, r)
+	handlers.InitBannerRoutes(srv, statsSrv, r, authMiddleware, logger)
+	handlers.InitJobRoutes(jobSrv, r, authMiddleware, logger)
+	handlers.InitExperimentRoutes(experimentSrv, r, authMiddleware, logger)
+	handlers.InitFeatureRoutes(featureSrv, r, authMiddleware, logger)
+	handlers.InitTagRoutes(tagSrv, r, authMiddleware, logger)
+	handlers.InitUserRoutes(userSrv, signer, cfg.Auth.DevMode, r, authMiddleware, logger)
+	handlers.InitCacheRoutes(warmupSrv, r, authMiddleware)
+	handlers.InitAuditRoutes(auditSrv, r, authMiddleware, logger)
 
 	httpServer := &http.Server{
-		Addr:         ":8080",
+		Addr:         cfg.HTTP.Addr,
 		Handler:      r,
-		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		ReadTimeout:  cfg.HTTP.ReadTimeout,
+		WriteTimeout: cfg.HTTP.WriteTimeout,
+		IdleTimeout:  cfg.HTTP.IdleTimeout,
+	}
+
+	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("server is starting", "addr", cfg.HTTP.Addr)
+		serverErr <- httpServer.ListenAndServe()
+	}()
+	healthSrv.SetReady(true)
+
+	select {
+	case err := <-serverErr:
+		if err != nil && err != http.ErrServerClosed {
+			fatal(logger, "could not start server", err)
+		}
+	case <-signalCtx.Done():
+		stop()
+		logger.Info("shutdown signal received, draining", "drain_period", cfg.HTTP.DrainPeriod)
+		healthSrv.SetReady(false)
+		time.Sleep(cfg.HTTP.DrainPeriod)
+
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+		defer cancelShutdown()
+
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			logger.Error("could not shut down server gracefully", "error", err)
+		}
+	}
+
+	logger.Info("stopping background workers")
+	cancel()
+	workers.Wait()
+	warmupSrv.Stop()
+
+	if err := rdb.Close(); err != nil {
+		logger.Error("could not close Redis client", "error", err)
+	}
+	pool.Close()
+
+	tracingCtx, cancelTracing := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancelTracing()
+
+	if err := shutdownTracing(tracingCtx); err != nil {
+		logger.Error("could not flush traces", "error", err)
+	}
+
+	logger.Info("server stopped")
+}
+
+func tracedRequest(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/metrics", "/healthz", "/readyz":
+		return false
 	}
 
-	log.Println("Server is starting...")
-	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("Could not start server: %v", err)
+	return true
+}
+
+func unmatched(handler http.Handler, mws ...mux.MiddlewareFunc) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		handler = mws[i](handler)
 	}
+
+	return handler
+}
+
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -1,6 +1,9 @@
 CREATE TABLE public.features (
     feature_id SERIAL PRIMARY KEY,
-    name VARCHAR(255) NOT NULL
+    name VARCHAR(255) NOT NULL,
+    cache_policy VARCHAR(16) NOT NULL DEFAULT 'default',
+    cache_ttl_seconds INTEGER,
+    rotation_mode VARCHAR(16) NOT NULL DEFAULT 'none'
 );
 
 CREATE TABLE public.tags (
@@ -13,15 +16,64 @@ CREATE TABLE public.banners (
     feature_id INTEGER NOT NULl,
     content JSONB NOT NULL,
     is_active BOOLEAN NOT NULL,
+    active_from TIMESTAMP WITH TIME ZONE,
+    active_until TIMESTAMP WITH TIME ZONE,
+    weight INTEGER NOT NULL DEFAULT 1 CHECK (weight > 0),
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (feature_id) REFERENCES public.features(feature_id) ON DELETE CASCADE
 );
 
+CREATE TABLE public.banner_versions (
+    banner_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    feature_id INTEGER NOT NULL,
+    tag_ids INTEGER[] NOT NULL,
+    content JSONB NOT NULL,
+    is_active BOOLEAN NOT NULL,
+    active_from TIMESTAMP WITH TIME ZONE,
+    active_until TIMESTAMP WITH TIME ZONE,
+    weight INTEGER NOT NULL DEFAULT 1,
+    author VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (banner_id) REFERENCES public.banners(banner_id) ON DELETE CASCADE,
+    PRIMARY KEY (banner_id, version)
+);
+
+CREATE TABLE public.jobs (
+    job_id SERIAL PRIMARY KEY,
+    kind VARCHAR(64) NOT NULL,
+    feature_id INTEGER,
+    tag_id INTEGER,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    total INTEGER NOT NULL DEFAULT 0,
+    processed INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    author VARCHAR(255) NOT NULL DEFAULT '',
+    request_id VARCHAR(128) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX jobs_status_idx ON public.jobs (status, job_id);
+
 CREATE TABLE public.users (
     user_id SERIAL PRIMARY KEY,
+    login VARCHAR(255) UNIQUE,
+    password_hash VARCHAR(255) NOT NULL DEFAULT '',
     token VARCHAR(255),
-    is_admin BOOLEAN NOT NULL DEFAULT false
+    is_admin BOOLEAN NOT NULL DEFAULT false,
+    role VARCHAR(16) NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX users_token_idx ON public.users (token) WHERE token <> '';
+
+CREATE TABLE public.user_features (
+    user_id INTEGER NOT NULL,
+    feature_id INTEGER NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES public.users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (feature_id) REFERENCES public.features(feature_id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, feature_id)
 );
 
 CREATE TABLE public.banner_tag (
@@ -32,6 +84,53 @@ CREATE TABLE public.banner_tag (
     PRIMARY KEY (banner_id, tag_id)
 );
 
+CREATE TABLE public.audit_log (
+    audit_id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    banner_id INTEGER NOT NULL,
+    changes JSONB NOT NULL,
+    request_id VARCHAR(128) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_log_banner_id_idx ON public.audit_log (banner_id);
+CREATE INDEX audit_log_actor_idx ON public.audit_log (actor);
+
+CREATE TABLE public.banner_stats (
+    banner_id INTEGER NOT NULL,
+    bucket TIMESTAMP WITH TIME ZONE NOT NULL,
+    impressions BIGINT NOT NULL DEFAULT 0,
+    clicks BIGINT NOT NULL DEFAULT 0,
+    FOREIGN KEY (banner_id) REFERENCES public.banners(banner_id) ON DELETE CASCADE,
+    PRIMARY KEY (banner_id, bucket)
+);
+
+CREATE TABLE public.experiments (
+    experiment_id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    feature_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'running',
+    winner_banner_id INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (feature_id) REFERENCES public.features(feature_id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES public.tags(tag_id) ON DELETE CASCADE,
+    FOREIGN KEY (winner_banner_id) REFERENCES public.banners(banner_id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX experiments_open_idx ON public.experiments (feature_id, tag_id) WHERE status <> 'concluded';
+
+CREATE TABLE public.experiment_variants (
+    experiment_id INTEGER NOT NULL,
+    banner_id INTEGER NOT NULL,
+    traffic INTEGER NOT NULL CHECK (traffic > 0 AND traffic <= 100),
+    FOREIGN KEY (experiment_id) REFERENCES public.experiments(experiment_id) ON DELETE CASCADE,
+    FOREIGN KEY (banner_id) REFERENCES public.banners(banner_id) ON DELETE CASCADE,
+    PRIMARY KEY (experiment_id, banner_id)
+);
+
 CREATE OR REPLACE FUNCTION check_unique_feature_tag_combination()
 RETURNS TRIGGER AS $$
 DECLARE
@@ -39,6 +138,10 @@ DECLARE
 BEGIN
     SELECT feature_id INTO v_banner_feature_id FROM banners WHERE banner_id = NEW.banner_id;
 
+    IF (SELECT rotation_mode FROM features WHERE feature_id = v_banner_feature_id) <> 'none' THEN
+        RETURN NEW;
+    END IF;
+
     IF (SELECT COUNT(*) FROM banner_tag
         JOIN banners ON banners.banner_id = banner_tag.banner_id
         WHERE banners.feature_id = v_banner_feature_id
@@ -62,8 +165,8 @@ INSERT INTO public.tags (name)
 SELECT 'Tag ' || i
 FROM generate_series(1, 800) AS i;
 
-INSERT INTO public.users (token, is_admin)
-SELECT '', i <= 3
+INSERT INTO public.users (login, token, is_admin, role)
+SELECT 'user' || i, '', i <= 3, CASE WHEN i <= 3 THEN 'admin' ELSE '' END
 FROM generate_series(1, 10) AS i;
 
 DO $$
@@ -112,5 +215,8 @@ BEGIN
     END LOOP;
 END $$;
 
-
-
+INSERT INTO public.banner_versions (banner_id, version, feature_id, tag_ids, content, is_active, created_at)
+SELECT b.banner_id, 1, b.feature_id, COALESCE(array_agg(bt.tag_id ORDER BY bt.tag_id) FILTER (WHERE bt.tag_id IS NOT NULL), '{}'), b.content, b.is_active, b.updated_at
+FROM public.banners b
+LEFT JOIN public.banner_tag bt ON b.banner_id = bt.banner_id
+GROUP BY b.banner_id;
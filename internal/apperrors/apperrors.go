@@ -0,0 +1,72 @@
+package apperrors
+
+import (
+	"banner-service/internal/i18n"
+	"errors"
+)
+
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrRateLimited  = errors.New("rate limited")
+)
+
+type FieldError struct {
+	Field   string
+	Message i18n.Message
+}
+
+type Error struct {
+	Kind    error
+	Message i18n.Message
+	Details []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	msg := i18n.Translate(i18n.English, e.Message)
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+
+	return []error{e.Kind}
+}
+
+func NotFound(message i18n.Message) *Error {
+	return &Error{Kind: ErrNotFound, Message: message}
+}
+
+func Conflict(message i18n.Message, err error) *Error {
+	return &Error{Kind: ErrConflict, Message: message, Err: err}
+}
+
+func Validation(message i18n.Message, details ...FieldError) *Error {
+	return &Error{Kind: ErrValidation, Message: message, Details: details}
+}
+
+func Unauthorized(message i18n.Message) *Error {
+	return &Error{Kind: ErrUnauthorized, Message: message}
+}
+
+func Forbidden(message i18n.Message) *Error {
+	return &Error{Kind: ErrForbidden, Message: message}
+}
+
+func RateLimited(message i18n.Message) *Error {
+	return &Error{Kind: ErrRateLimited, Message: message}
+}
+
+func InvalidField(field string, message i18n.Message) *Error {
+	return Validation(i18n.InvalidRequest, FieldError{Field: field, Message: message})
+}
@@ -0,0 +1,68 @@
+package apperrors
+
+import (
+	"banner-service/internal/i18n"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+var kinds = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{ErrNotFound, http.StatusNotFound, "not_found"},
+	{ErrConflict, http.StatusConflict, "conflict"},
+	{ErrValidation, http.StatusBadRequest, "validation_failed"},
+	{ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+	{ErrForbidden, http.StatusForbidden, "forbidden"},
+	{ErrRateLimited, http.StatusTooManyRequests, "rate_limited"},
+}
+
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details []detailBody `json:"details,omitempty"`
+}
+
+type detailBody struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	lang := i18n.FromRequest(r)
+	status := http.StatusInternalServerError
+	body := errorBody{Code: "internal", Message: i18n.Translate(lang, i18n.InternalError)}
+
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		for _, k := range kinds {
+			if appErr.Kind == k.kind {
+				status = k.status
+				body = errorBody{Code: k.code, Message: i18n.Translate(lang, appErr.Message)}
+				for _, detail := range appErr.Details {
+					body.Details = append(body.Details, detailBody{Field: detail.Field, Message: i18n.Translate(lang, detail.Message)})
+				}
+				break
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Language", lang)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Add("Vary", "Accept-Language")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(errorResponse{Error: body})
+}
+
+func IsExpected(err error) bool {
+	var appErr *Error
+	return errors.As(err, &appErr)
+}
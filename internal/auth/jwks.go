@@ -0,0 +1,272 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	jwksFetchTimeout     = 5 * time.Second
+	jwksMinRefreshPeriod = 30 * time.Second
+)
+
+var ErrKeyNotFound = errors.New("signing key not found")
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+type verificationKey struct {
+	alg string
+	key interface{}
+}
+
+type KeySet struct {
+	file            string
+	url             string
+	refreshInterval time.Duration
+	client          *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]verificationKey
+	refreshedAt time.Time
+	attemptedAt time.Time
+}
+
+func NewFileKeySet(file string) (*KeySet, error) {
+	ks := &KeySet{file: file}
+	if err := ks.Refresh(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+func NewURLKeySet(url string, refreshInterval time.Duration) (*KeySet, error) {
+	ks := &KeySet{
+		url:             url,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: jwksFetchTimeout},
+	}
+	if err := ks.Refresh(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+func (ks *KeySet) key(ctx context.Context, kid string) (verificationKey, error) {
+	ks.mu.RLock()
+	key, ok := ks.lookup(kid)
+	stale := ks.refreshInterval > 0 && time.Since(ks.refreshedAt) > ks.refreshInterval
+	ks.mu.RUnlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+
+	if ks.claimRefresh() {
+		ctx, cancel := context.WithTimeout(ctx, jwksFetchTimeout)
+		defer cancel()
+
+		if err := ks.Refresh(ctx); err != nil && !ok {
+			return verificationKey{}, err
+		}
+
+		ks.mu.RLock()
+		key, ok = ks.lookup(kid)
+		ks.mu.RUnlock()
+	}
+
+	if !ok {
+		return verificationKey{}, ErrKeyNotFound
+	}
+
+	return key, nil
+}
+
+func (ks *KeySet) Refresh(ctx context.Context) error {
+	data, err := ks.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys, err := parseJWKS(data, ks.file != "")
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.refreshedAt = time.Now()
+	ks.attemptedAt = ks.refreshedAt
+	if err != nil {
+		return err
+	}
+	ks.keys = keys
+
+	return nil
+}
+
+func (ks *KeySet) claimRefresh() bool {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if time.Since(ks.attemptedAt) < jwksMinRefreshPeriod {
+		return false
+	}
+	ks.attemptedAt = time.Now()
+
+	return true
+}
+
+func (ks *KeySet) lookup(kid string) (verificationKey, bool) {
+	if kid == "" && len(ks.keys) == 1 {
+		for _, key := range ks.keys {
+			return key, true
+		}
+	}
+
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+func (ks *KeySet) load(ctx context.Context) ([]byte, error) {
+	if ks.file != "" {
+		return os.ReadFile(ks.file)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ks.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected JWKS response status: %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+func (ks *KeySet) hasSymmetricKeys() bool {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	for _, key := range ks.keys {
+		if _, ok := key.key.([]byte); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseJWKS(data []byte, allowSymmetric bool) (map[string]verificationKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]verificationKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if jwk.Kty == "oct" && !allowSymmetric {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWK %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = verificationKey{alg: jwk.Alg, key: key}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS contains no signing keys")
+	}
+
+	return keys, nil
+}
+
+func (jwk jsonWebKey) publicKey() (interface{}, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(jwk.K)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
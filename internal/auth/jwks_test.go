@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"banner-service/internal/config"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+
+	return key
+}
+
+func writeJWKS(t *testing.T, file string, keys map[string]*rsa.PrivateKey) {
+	t.Helper()
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	for kid, key := range keys {
+		set.Keys = append(set.Keys, jsonWebKey{
+			Kty: "RSA",
+			Kid: kid,
+			Alg: "RS256",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("marshal JWKS: %v", err)
+	}
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		t.Fatalf("write JWKS: %v", err)
+	}
+}
+
+func TestFileKeySetLookup(t *testing.T) {
+	key := generateKey(t)
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, file, map[string]*rsa.PrivateKey{"a": key})
+
+	ks, err := NewFileKeySet(file)
+	if err != nil {
+		t.Fatalf("NewFileKeySet: %v", err)
+	}
+
+	got, err := ks.key(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("key(a): %v", err)
+	}
+	if got.alg != "RS256" {
+		t.Errorf("alg = %q, want RS256", got.alg)
+	}
+	if pub, ok := got.key.(*rsa.PublicKey); !ok || !pub.Equal(&key.PublicKey) {
+		t.Errorf("key(a) returned %T, want the generated public key", got.key)
+	}
+
+	got, err = ks.key(context.Background(), "")
+	if err != nil || got.alg != "RS256" {
+		t.Errorf("key(\"\") with a single key = %v, %v, want that key", got, err)
+	}
+}
+
+func TestKeySetRateLimitsUnknownKidRefresh(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, file, map[string]*rsa.PrivateKey{"a": generateKey(t)})
+
+	ks, err := NewFileKeySet(file)
+	if err != nil {
+		t.Fatalf("NewFileKeySet: %v", err)
+	}
+
+	writeJWKS(t, file, map[string]*rsa.PrivateKey{"a": generateKey(t), "b": generateKey(t)})
+
+	if _, err := ks.key(context.Background(), "b"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("key(b) right after loading = %v, want ErrKeyNotFound", err)
+	}
+
+	ks.mu.Lock()
+	ks.attemptedAt = time.Now().Add(-jwksMinRefreshPeriod)
+	ks.mu.Unlock()
+
+	if _, err := ks.key(context.Background(), "b"); err != nil {
+		t.Fatalf("key(b) after the refresh period: %v", err)
+	}
+	if _, err := ks.key(context.Background(), "c"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("key(c) right after a refresh = %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestKeySetRefreshUsesRequestContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ks := &KeySet{url: srv.URL, client: srv.Client()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := ks.key(ctx, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("key(a) = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > jwksFetchTimeout {
+		t.Errorf("key(a) took %v, want it to stop with the request context", elapsed)
+	}
+}
+
+func TestVerifierWithJWKSFile(t *testing.T) {
+	key := generateKey(t)
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, file, map[string]*rsa.PrivateKey{"a": key})
+
+	v, err := NewVerifier(config.AuthConfig{JWKSFile: file})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "user", "exp": time.Now().Add(time.Minute).Unix()})
+	token.Header["kid"] = "a"
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	claims, err := v.Verify(context.Background(), signed)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if claims["sub"] != "user" {
+		t.Errorf("sub = %v, want user", claims["sub"])
+	}
+
+	token.Header["kid"] = "b"
+	signed, err = token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), signed); err == nil {
+		t.Error("Verify accepted a token signed with an unknown kid")
+	}
+}
+
+func TestVerifierSymmetricKeys(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	rsaKey := generateKey(t)
+	data, err := json.Marshal(map[string][]jsonWebKey{"keys": {
+		{Kty: "oct", Kid: "h", Alg: "HS256", K: base64.RawURLEncoding.EncodeToString(secret)},
+		{Kty: "RSA", Kid: "r", Alg: "RS256", N: base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()), E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes())},
+	}})
+	if err != nil {
+		t.Fatalf("marshal JWKS: %v", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		t.Fatalf("write JWKS: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	defer srv.Close()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user", "exp": time.Now().Add(time.Minute).Unix()})
+	token.Header["kid"] = "h"
+	signed, err := token.SignedString(secret)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		cfg     config.AuthConfig
+		wantErr bool
+	}{
+		{"JWKS file", config.AuthConfig{JWKSFile: file}, false},
+		{"JWKS URL", config.AuthConfig{JWKSURL: srv.URL, JWKSRefreshInterval: time.Minute}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewVerifier(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewVerifier: %v", err)
+			}
+
+			_, err = v.Verify(context.Background(), signed)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("Verify HS256 token error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
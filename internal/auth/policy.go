@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type Role string
+
+const (
+	RoleUser      Role = ""
+	RoleViewer    Role = "viewer"
+	RoleEditor    Role = "editor"
+	RolePublisher Role = "publisher"
+	RoleAdmin     Role = "admin"
+)
+
+type Permission string
+
+const (
+	PermBannerRead    Permission = "banner:read"
+	PermBannerWrite   Permission = "banner:write"
+	PermBannerPublish Permission = "banner:publish"
+	PermBannerDelete  Permission = "banner:delete"
+	PermCatalogManage Permission = "catalog:manage"
+	PermUserManage    Permission = "user:manage"
+	PermCacheManage   Permission = "cache:manage"
+	PermAuditRead     Permission = "audit:read"
+)
+
+type principalKey struct{}
+
+type Principal struct {
+	UserID     string
+	Role       Role
+	FeatureIDs []int
+}
+
+func ParseRole(s string) (Role, bool) {
+	switch role := Role(s); role {
+	case RoleUser, RoleViewer, RoleEditor, RolePublisher, RoleAdmin:
+		return role, true
+	}
+
+	return RoleUser, false
+}
+
+func (r Role) permissions() []Permission {
+	switch r {
+	case RoleViewer:
+		return []Permission{PermBannerRead}
+	case RoleEditor:
+		return []Permission{PermBannerRead, PermBannerWrite}
+	case RolePublisher:
+		return []Permission{PermBannerRead, PermBannerWrite, PermBannerPublish, PermBannerDelete}
+	case RoleAdmin:
+		return []Permission{PermBannerRead, PermBannerWrite, PermBannerPublish, PermBannerDelete, PermCatalogManage, PermUserManage, PermCacheManage, PermAuditRead}
+	}
+
+	return nil
+}
+
+func (p *Principal) IsAdmin() bool {
+	return p.Role == RoleAdmin
+}
+
+func (p *Principal) Can(perm Permission) bool {
+	for _, granted := range p.Role.permissions() {
+		if granted == perm {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Principal) CanForFeature(perm Permission, featureID int) bool {
+	if !p.Can(perm) {
+		return false
+	}
+	if p.IsAdmin() {
+		return true
+	}
+	if featureID <= 0 {
+		return false
+	}
+
+	for _, id := range p.FeatureIDs {
+		if id == featureID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Principal) FeatureScope() []int {
+	if p.IsAdmin() {
+		return nil
+	}
+	if p.FeatureIDs == nil {
+		return []int{}
+	}
+
+	return p.FeatureIDs
+}
+
+func PrincipalFromClaims(claims jwt.MapClaims) *Principal {
+	p := &Principal{}
+	p.UserID, _ = claims["sub"].(string)
+
+	if roleName, ok := claims["role"].(string); ok {
+		p.Role, _ = ParseRole(roleName)
+	}
+	if adminFlag, ok := claims["admin"].(bool); ok && adminFlag {
+		p.Role = RoleAdmin
+	}
+
+	if featureIDs, ok := claims["feature_ids"].([]interface{}); ok {
+		for _, v := range featureIDs {
+			if id, ok := v.(float64); ok && id > 0 {
+				p.FeatureIDs = append(p.FeatureIDs, int(id))
+			}
+		}
+	}
+
+	return p
+}
+
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
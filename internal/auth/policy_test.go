@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestPrincipalCanForFeature(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal Principal
+		perm      Permission
+		featureID int
+		want      bool
+	}{
+		{"admin any feature", Principal{Role: RoleAdmin}, PermBannerDelete, 42, true},
+		{"admin without feature", Principal{Role: RoleAdmin}, PermBannerDelete, 0, true},
+		{"publisher in scope", Principal{Role: RolePublisher, FeatureIDs: []int{1, 2}}, PermBannerDelete, 2, true},
+		{"publisher out of scope", Principal{Role: RolePublisher, FeatureIDs: []int{1, 2}}, PermBannerDelete, 3, false},
+		{"publisher without feature", Principal{Role: RolePublisher, FeatureIDs: []int{1, 2}}, PermBannerDelete, 0, false},
+		{"editor cannot publish", Principal{Role: RoleEditor, FeatureIDs: []int{1}}, PermBannerPublish, 1, false},
+		{"viewer reads in scope", Principal{Role: RoleViewer, FeatureIDs: []int{1}}, PermBannerRead, 1, true},
+		{"user has no permissions", Principal{Role: RoleUser, FeatureIDs: []int{1}}, PermBannerRead, 1, false},
+		{"publisher cannot manage catalog", Principal{Role: RolePublisher, FeatureIDs: []int{1}}, PermCatalogManage, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.principal.CanForFeature(tt.perm, tt.featureID); got != tt.want {
+				t.Errorf("CanForFeature(%s, %d) = %v, want %v", tt.perm, tt.featureID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrincipalFeatureScope(t *testing.T) {
+	if scope := (&Principal{Role: RoleAdmin, FeatureIDs: []int{1}}).FeatureScope(); scope != nil {
+		t.Errorf("admin scope = %v, want nil (unrestricted)", scope)
+	}
+	if scope := (&Principal{Role: RoleEditor}).FeatureScope(); scope == nil || len(scope) != 0 {
+		t.Errorf("unscoped editor scope = %#v, want an empty slice", scope)
+	}
+	if scope := (&Principal{Role: RoleEditor, FeatureIDs: []int{4, 5}}).FeatureScope(); !slices.Equal(scope, []int{4, 5}) {
+		t.Errorf("scoped editor scope = %v, want [4 5]", scope)
+	}
+}
+
+func TestPrincipalFromClaims(t *testing.T) {
+	p := PrincipalFromClaims(jwt.MapClaims{
+		"sub":         "42",
+		"role":        "publisher",
+		"feature_ids": []interface{}{float64(1), float64(-2), "3", float64(4)},
+	})
+	if p.UserID != "42" || p.Role != RolePublisher || !slices.Equal(p.FeatureIDs, []int{1, 4}) {
+		t.Errorf("PrincipalFromClaims = %+v, want user 42, publisher, features [1 4]", p)
+	}
+
+	if p := PrincipalFromClaims(jwt.MapClaims{"role": "viewer", "admin": true}); p.Role != RoleAdmin {
+		t.Errorf("admin flag gave role %q, want admin", p.Role)
+	}
+	if p := PrincipalFromClaims(jwt.MapClaims{"role": "root"}); p.Role != RoleUser {
+		t.Errorf("unknown role gave %q, want the plain user role", p.Role)
+	}
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"banner-service/internal/config"
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type Signer struct {
+	method   jwt.SigningMethod
+	key      interface{}
+	keyID    string
+	issuer   string
+	audience string
+}
+
+func NewSigner(cfg config.AuthConfig) (*Signer, error) {
+	s := &Signer{
+		keyID:    cfg.KeyID,
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+	}
+
+	switch {
+	case cfg.PrivateKeyFile != "":
+		key, err := loadPrivateKey(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		s.key = key
+		s.method = jwt.SigningMethodRS256
+		if ecKey, ok := key.(*ecdsa.PrivateKey); ok {
+			s.method = ecdsaMethodFor(ecKey)
+		}
+	case cfg.HMACSecret != "":
+		s.key = []byte(cfg.HMACSecret)
+		s.method = jwt.SigningMethodHS256
+	default:
+		return nil, nil
+	}
+
+	return s, nil
+}
+
+func (s *Signer) Sign(claims jwt.MapClaims, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(ttl).Unix()
+	if s.issuer != "" {
+		claims["iss"] = s.issuer
+	}
+	if s.audience != "" {
+		claims["aud"] = s.audience
+	}
+
+	token := jwt.NewWithClaims(s.method, claims)
+	if s.keyID != "" {
+		token.Header["kid"] = s.keyID
+	}
+
+	return token.SignedString(s.key)
+}
+
+func loadPrivateKey(file string) (interface{}, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseECPrivateKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unsupported private key in %s", file)
+}
+
+func ecdsaMethodFor(key *ecdsa.PrivateKey) jwt.SigningMethod {
+	switch key.Curve.Params().BitSize {
+	case 384:
+		return jwt.SigningMethodES384
+	case 521:
+		return jwt.SigningMethodES512
+	}
+
+	return jwt.SigningMethodES256
+}
@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"banner-service/internal/config"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var (
+	hmacMethods   = []string{"HS256", "HS384", "HS512"}
+	rsaMethods    = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+	ecdsaMethods  = []string{"ES256", "ES384", "ES512"}
+	ErrNoAuthKeys = errors.New("no JWT verification keys configured")
+)
+
+type Verifier struct {
+	hmacSecret []byte
+	publicKey  interface{}
+	keySet     *KeySet
+	issuer     string
+	audience   string
+	parser     *jwt.Parser
+}
+
+func NewVerifier(cfg config.AuthConfig) (*Verifier, error) {
+	v := &Verifier{
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+	}
+
+	var methods []string
+	if cfg.HMACSecret != "" {
+		v.hmacSecret = []byte(cfg.HMACSecret)
+		methods = append(methods, hmacMethods...)
+	}
+
+	if cfg.PublicKeyFile != "" {
+		key, err := loadPublicKey(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		v.publicKey = key
+	} else if cfg.PrivateKeyFile != "" {
+		key, err := loadPrivateKey(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		v.publicKey = publicKeyOf(key)
+	}
+
+	switch {
+	case cfg.JWKSFile != "":
+		keySet, err := NewFileKeySet(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.keySet = keySet
+	case cfg.JWKSURL != "":
+		keySet, err := NewURLKeySet(cfg.JWKSURL, cfg.JWKSRefreshInterval)
+		if err != nil {
+			return nil, err
+		}
+		v.keySet = keySet
+	}
+
+	if v.publicKey != nil || v.keySet != nil {
+		methods = append(methods, rsaMethods...)
+		methods = append(methods, ecdsaMethods...)
+	}
+	if v.keySet != nil && v.hmacSecret == nil && v.keySet.hasSymmetricKeys() {
+		methods = append(methods, hmacMethods...)
+	}
+
+	if len(methods) == 0 {
+		return nil, ErrNoAuthKeys
+	}
+	v.parser = jwt.NewParser(jwt.WithValidMethods(methods))
+
+	return v, nil
+}
+
+func (v *Verifier) Verify(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
+	token, err := v.parser.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return v.keyFunc(ctx, token)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("token has no expiration time")
+	}
+
+	if v.issuer != "" && !claims.VerifyIssuer(v.issuer, true) {
+		return nil, errors.New("invalid token issuer")
+	}
+	if v.audience != "" && !claims.VerifyAudience(v.audience, true) {
+		return nil, errors.New("invalid token audience")
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) keyFunc(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	alg := token.Method.Alg()
+
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok && v.hmacSecret != nil {
+		return v.hmacSecret, nil
+	}
+
+	if v.keySet != nil {
+		kid, _ := token.Header["kid"].(string)
+		key, err := v.keySet.key(ctx, kid)
+		if errors.Is(err, ErrKeyNotFound) && v.publicKey != nil {
+			return v.publicKey, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if key.alg != "" && key.alg != alg {
+			return nil, fmt.Errorf("unexpected signing method for key %q: %v", kid, alg)
+		}
+
+		return key.key, nil
+	}
+
+	if v.publicKey != nil {
+		return v.publicKey, nil
+	}
+
+	return nil, fmt.Errorf("unexpected signing method: %v", alg)
+}
+
+func loadPublicKey(file string) (interface{}, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseECPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unsupported public key in %s", file)
+}
+
+func publicKeyOf(privateKey interface{}) interface{} {
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return &key.PublicKey
+	case *ecdsa.PrivateKey:
+		return &key.PublicKey
+	}
+
+	return nil
+}
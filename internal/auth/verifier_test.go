@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"banner-service/internal/config"
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func writePublicKey(t *testing.T, file string, key *rsa.PrivateKey) {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write public key: %v", err)
+	}
+}
+
+func TestVerifierVerify(t *testing.T) {
+	jwksKey, fileKey, otherKey := generateKey(t), generateKey(t), generateKey(t)
+	dir := t.TempDir()
+	writeJWKS(t, filepath.Join(dir, "jwks.json"), map[string]*rsa.PrivateKey{"a": jwksKey})
+	writePublicKey(t, filepath.Join(dir, "public.pem"), fileKey)
+
+	v, err := NewVerifier(config.AuthConfig{JWKSFile: filepath.Join(dir, "jwks.json"), PublicKeyFile: filepath.Join(dir, "public.pem")})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+
+	exp := time.Now().Add(time.Minute).Unix()
+	tests := []struct {
+		name    string
+		key     *rsa.PrivateKey
+		kid     string
+		claims  jwt.MapClaims
+		wantErr bool
+	}{
+		{"key set key", jwksKey, "a", jwt.MapClaims{"sub": "user", "exp": exp}, false},
+		{"unknown kid falls back to the public key", fileKey, "rotated", jwt.MapClaims{"sub": "user", "exp": exp}, false},
+		{"unknown key", otherKey, "rotated", jwt.MapClaims{"sub": "user", "exp": exp}, true},
+		{"key set key under the wrong kid", jwksKey, "rotated", jwt.MapClaims{"sub": "user", "exp": exp}, true},
+		{"missing expiration", jwksKey, "a", jwt.MapClaims{"sub": "user"}, true},
+		{"expired", jwksKey, "a", jwt.MapClaims{"sub": "user", "exp": time.Now().Add(-time.Minute).Unix()}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, tt.claims)
+			if tt.kid != "" {
+				token.Header["kid"] = tt.kid
+			}
+			signed, err := token.SignedString(tt.key)
+			if err != nil {
+				t.Fatalf("sign token: %v", err)
+			}
+
+			_, err = v.Verify(context.Background(), signed)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("Verify error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
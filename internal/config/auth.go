@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+type AuthConfig struct {
+	HMACSecret               string        `yaml:"hmac_secret"`
+	PrivateKeyFile           string        `yaml:"private_key_file"`
+	PublicKeyFile            string        `yaml:"public_key_file"`
+	JWKSFile                 string        `yaml:"jwks_file"`
+	JWKSURL                  string        `yaml:"jwks_url"`
+	JWKSRefreshInterval      time.Duration `yaml:"jwks_refresh_interval"`
+	KeyID                    string        `yaml:"key_id"`
+	Issuer                   string        `yaml:"issuer"`
+	Audience                 string        `yaml:"audience"`
+	DevMode                  bool          `yaml:"dev_mode"`
+	AdminLogin               string        `yaml:"admin_login"`
+	AdminPassword            string        `yaml:"admin_password"`
+	LoginAttemptsPerMinute   int           `yaml:"login_attempts_per_minute"`
+	LoginIPAttemptsPerMinute int           `yaml:"login_ip_attempts_per_minute"`
+}
@@ -0,0 +1,5 @@
+package config
+
+type BannersConfig struct {
+	VersionsLimit int `yaml:"versions_limit"`
+}
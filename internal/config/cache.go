@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+type CacheConfig struct {
+	TTL                  time.Duration `yaml:"ttl"`
+	NotFoundTTL          time.Duration `yaml:"not_found_ttl"`
+	StaleWhileRevalidate time.Duration `yaml:"stale_while_revalidate"`
+	LoadTimeout          time.Duration `yaml:"load_timeout"`
+	LocalSize            int           `yaml:"local_size"`
+	LocalTTL             time.Duration `yaml:"local_ttl"`
+	LocalJitter          float64       `yaml:"local_jitter"`
+	WarmupOnStartup      bool          `yaml:"warmup_on_startup"`
+	WarmupRate           int           `yaml:"warmup_rate"`
+}
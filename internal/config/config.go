@@ -0,0 +1,414 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "REDACTED"
+
+var (
+	sslModes         = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	logFormats       = []string{"json", "text"}
+	tracingExporters = []string{"none", "otlp", "stdout"}
+)
+
+type Config struct {
+	HTTP     HTTPConfig     `yaml:"http"`
+	Postgres PostgresConfig `yaml:"postgres"`
+	Redis    RedisConfig    `yaml:"redis"`
+	Auth     AuthConfig     `yaml:"auth"`
+	Cache    CacheConfig    `yaml:"cache"`
+	Banners  BannersConfig  `yaml:"banners"`
+	Stats    StatsConfig    `yaml:"stats"`
+	Log      LogConfig      `yaml:"log"`
+	Tracing  TracingConfig  `yaml:"tracing"`
+}
+
+func Default() Config {
+	return Config{
+		HTTP: HTTPConfig{
+			Addr:               ":8080",
+			ReadTimeout:        5 * time.Second,
+			WriteTimeout:       10 * time.Second,
+			IdleTimeout:        time.Minute,
+			DrainPeriod:        5 * time.Second,
+			ShutdownTimeout:    15 * time.Second,
+			HealthCheckTimeout: time.Second,
+		},
+		Postgres: PostgresConfig{
+			Host:            "localhost",
+			Port:            5432,
+			SSLMode:         "prefer",
+			MaxConns:        25,
+			MaxConnIdleTime: 15 * time.Minute,
+		},
+		Redis: RedisConfig{
+			Addr:         "localhost:6379",
+			DialTimeout:  5 * time.Second,
+			ReadTimeout:  3 * time.Second,
+			WriteTimeout: 3 * time.Second,
+			PoolSize:     10,
+			MinIdleConns: 2,
+			MaxConnAge:   30 * time.Minute,
+		},
+		Auth: AuthConfig{
+			JWKSRefreshInterval:      10 * time.Minute,
+			LoginAttemptsPerMinute:   5,
+			LoginIPAttemptsPerMinute: 30,
+		},
+		Cache: CacheConfig{
+			TTL:             5 * time.Minute,
+			NotFoundTTL:     30 * time.Second,
+			LoadTimeout:     3 * time.Second,
+			LocalSize:       10000,
+			LocalTTL:        30 * time.Second,
+			LocalJitter:     0.1,
+			WarmupOnStartup: true,
+			WarmupRate:      1000,
+		},
+		Banners: BannersConfig{
+			VersionsLimit: 3,
+		},
+		Stats: StatsConfig{
+			FlushInterval:   10 * time.Second,
+			BufferSize:      10000,
+			ClicksPerMinute: 60,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "banner-service",
+			SampleRatio: 1,
+		},
+	}
+}
+
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+
+	env := &envLoader{}
+	cfg.loadEnv(env)
+	if len(env.errs) > 0 {
+		return Config{}, fmt.Errorf("invalid environment: %w", errors.Join(env.errs...))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+func (cfg *Config) loadFile(path string) error {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+	default:
+		return fmt.Errorf("config file %s: unsupported format, only YAML (.yaml or .yml) is supported", path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && err != io.EOF {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (cfg *Config) loadEnv(env *envLoader) {
+	env.string("HTTP_ADDR", &cfg.HTTP.Addr)
+	env.duration("HTTP_READ_TIMEOUT", &cfg.HTTP.ReadTimeout)
+	env.duration("HTTP_WRITE_TIMEOUT", &cfg.HTTP.WriteTimeout)
+	env.duration("HTTP_IDLE_TIMEOUT", &cfg.HTTP.IdleTimeout)
+	env.duration("HTTP_DRAIN_PERIOD", &cfg.HTTP.DrainPeriod)
+	env.duration("HTTP_SHUTDOWN_TIMEOUT", &cfg.HTTP.ShutdownTimeout)
+	env.duration("HTTP_HEALTH_CHECK_TIMEOUT", &cfg.HTTP.HealthCheckTimeout)
+
+	env.string("DB_URL", &cfg.Postgres.ConnStr)
+	env.string("DB_HOST", &cfg.Postgres.Host)
+	env.int("DB_PORT", &cfg.Postgres.Port)
+	env.string("DB_USER", &cfg.Postgres.User)
+	env.string("DB_PASS", &cfg.Postgres.Password)
+	env.string("DB_NAME", &cfg.Postgres.Database)
+	env.string("DB_SSLMODE", &cfg.Postgres.SSLMode)
+	env.int32("DB_MAX_CONNS", &cfg.Postgres.MaxConns)
+	env.duration("DB_MAX_CONN_IDLE_TIME", &cfg.Postgres.MaxConnIdleTime)
+
+	env.string("REDIS_ADDR", &cfg.Redis.Addr)
+	env.hostPort("REDIS_HOST", "REDIS_PORT", &cfg.Redis.Addr)
+	env.string("REDIS_PASSWORD", &cfg.Redis.Password)
+	env.int("REDIS_DB", &cfg.Redis.DB)
+	env.duration("REDIS_DIAL_TIMEOUT", &cfg.Redis.DialTimeout)
+	env.duration("REDIS_READ_TIMEOUT", &cfg.Redis.ReadTimeout)
+	env.duration("REDIS_WRITE_TIMEOUT", &cfg.Redis.WriteTimeout)
+	env.int("REDIS_POOL_SIZE", &cfg.Redis.PoolSize)
+	env.int("REDIS_MIN_IDLE_CONNS", &cfg.Redis.MinIdleConns)
+	env.duration("REDIS_MAX_CONN_AGE", &cfg.Redis.MaxConnAge)
+
+	env.string("JWT_SECRET", &cfg.Auth.HMACSecret)
+	env.string("JWT_PRIVATE_KEY_FILE", &cfg.Auth.PrivateKeyFile)
+	env.string("JWT_PUBLIC_KEY_FILE", &cfg.Auth.PublicKeyFile)
+	env.string("JWT_JWKS_FILE", &cfg.Auth.JWKSFile)
+	env.string("JWT_JWKS_URL", &cfg.Auth.JWKSURL)
+	env.duration("JWT_JWKS_REFRESH_INTERVAL", &cfg.Auth.JWKSRefreshInterval)
+	env.string("JWT_KEY_ID", &cfg.Auth.KeyID)
+	env.string("JWT_ISSUER", &cfg.Auth.Issuer)
+	env.string("JWT_AUDIENCE", &cfg.Auth.Audience)
+	env.bool("DEV_MODE", &cfg.Auth.DevMode)
+	env.string("ADMIN_LOGIN", &cfg.Auth.AdminLogin)
+	env.string("ADMIN_PASSWORD", &cfg.Auth.AdminPassword)
+	env.int("LOGIN_ATTEMPTS_PER_MINUTE", &cfg.Auth.LoginAttemptsPerMinute)
+	env.int("LOGIN_IP_ATTEMPTS_PER_MINUTE", &cfg.Auth.LoginIPAttemptsPerMinute)
+
+	env.duration("CACHE_TTL", &cfg.Cache.TTL)
+	env.duration("CACHE_NOT_FOUND_TTL", &cfg.Cache.NotFoundTTL)
+	env.duration("CACHE_STALE_WHILE_REVALIDATE", &cfg.Cache.StaleWhileRevalidate)
+	env.duration("CACHE_LOAD_TIMEOUT", &cfg.Cache.LoadTimeout)
+	env.int("CACHE_LOCAL_SIZE", &cfg.Cache.LocalSize)
+	env.duration("CACHE_LOCAL_TTL", &cfg.Cache.LocalTTL)
+	env.float("CACHE_LOCAL_JITTER", &cfg.Cache.LocalJitter)
+	env.bool("CACHE_WARMUP_ON_STARTUP", &cfg.Cache.WarmupOnStartup)
+	env.int("CACHE_WARMUP_RATE", &cfg.Cache.WarmupRate)
+
+	env.int("BANNER_VERSIONS_LIMIT", &cfg.Banners.VersionsLimit)
+
+	env.duration("STATS_FLUSH_INTERVAL", &cfg.Stats.FlushInterval)
+	env.int("STATS_BUFFER_SIZE", &cfg.Stats.BufferSize)
+	env.int("STATS_CLICKS_PER_MINUTE", &cfg.Stats.ClicksPerMinute)
+
+	env.string("LOG_LEVEL", &cfg.Log.Level)
+	env.string("LOG_FORMAT", &cfg.Log.Format)
+
+	env.string("TRACING_EXPORTER", &cfg.Tracing.Exporter)
+	env.string("TRACING_ENDPOINT", &cfg.Tracing.Endpoint)
+	env.bool("TRACING_INSECURE", &cfg.Tracing.Insecure)
+	env.string("TRACING_FILE", &cfg.Tracing.File)
+	env.string("OTEL_SERVICE_NAME", &cfg.Tracing.ServiceName)
+	env.float("TRACING_SAMPLE_RATIO", &cfg.Tracing.SampleRatio)
+}
+
+func (cfg Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(cfg.HTTP.Addr != "", "http.addr must not be empty")
+	check(cfg.HTTP.ReadTimeout > 0, "http.read_timeout must be positive")
+	check(cfg.HTTP.WriteTimeout > 0, "http.write_timeout must be positive")
+	check(cfg.HTTP.IdleTimeout >= 0, "http.idle_timeout must not be negative")
+	check(cfg.HTTP.DrainPeriod >= 0, "http.drain_period must not be negative")
+	check(cfg.HTTP.ShutdownTimeout > 0, "http.shutdown_timeout must be positive")
+	check(cfg.HTTP.HealthCheckTimeout > 0, "http.health_check_timeout must be positive")
+
+	if cfg.Postgres.ConnStr == "" {
+		check(cfg.Postgres.Host != "", "postgres.host must not be empty")
+		check(cfg.Postgres.Port > 0 && cfg.Postgres.Port <= 65535, "postgres.port %d is out of range", cfg.Postgres.Port)
+		check(cfg.Postgres.User != "", "postgres.user must not be empty")
+		check(cfg.Postgres.Database != "", "postgres.database must not be empty")
+		check(slices.Contains(sslModes, cfg.Postgres.SSLMode), "postgres.sslmode %q must be one of %v", cfg.Postgres.SSLMode, sslModes)
+	}
+	check(cfg.Postgres.MaxConns > 0, "postgres.max_conns must be positive")
+	check(cfg.Postgres.MaxConnIdleTime >= 0, "postgres.max_conn_idle_time must not be negative")
+
+	if _, _, err := net.SplitHostPort(cfg.Redis.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("redis.addr %q: %w", cfg.Redis.Addr, err))
+	}
+	check(cfg.Redis.DB >= 0, "redis.db must not be negative")
+	check(cfg.Redis.PoolSize > 0, "redis.pool_size must be positive")
+	check(cfg.Redis.MinIdleConns >= 0 && cfg.Redis.MinIdleConns <= cfg.Redis.PoolSize, "redis.min_idle_conns must be between 0 and redis.pool_size")
+	check(cfg.Redis.DialTimeout > 0, "redis.dial_timeout must be positive")
+	check(cfg.Redis.ReadTimeout > 0, "redis.read_timeout must be positive")
+	check(cfg.Redis.WriteTimeout > 0, "redis.write_timeout must be positive")
+
+	check(cfg.Auth.HMACSecret != "" || cfg.Auth.PrivateKeyFile != "" || cfg.Auth.PublicKeyFile != "" || cfg.Auth.JWKSFile != "" || cfg.Auth.JWKSURL != "",
+		"auth: one of hmac_secret, private_key_file, public_key_file, jwks_file or jwks_url must be set")
+	check(cfg.Auth.JWKSFile == "" || cfg.Auth.JWKSURL == "", "auth.jwks_file and auth.jwks_url are mutually exclusive")
+	check(cfg.Auth.JWKSRefreshInterval > 0, "auth.jwks_refresh_interval must be positive")
+	check((cfg.Auth.AdminLogin == "") == (cfg.Auth.AdminPassword == ""), "auth.admin_login and auth.admin_password must be set together")
+	check(cfg.Auth.LoginAttemptsPerMinute > 0, "auth.login_attempts_per_minute must be positive")
+	check(cfg.Auth.LoginIPAttemptsPerMinute > 0, "auth.login_ip_attempts_per_minute must be positive")
+
+	check(cfg.Cache.TTL > 0, "cache.ttl must be positive")
+	check(cfg.Cache.NotFoundTTL > 0, "cache.not_found_ttl must be positive")
+	check(cfg.Cache.StaleWhileRevalidate >= 0, "cache.stale_while_revalidate must not be negative")
+	check(cfg.Cache.LoadTimeout > 0, "cache.load_timeout must be positive")
+	check(cfg.Cache.LocalSize >= 0, "cache.local_size must not be negative")
+	check(cfg.Cache.LocalSize == 0 || cfg.Cache.LocalTTL > 0, "cache.local_ttl must be positive when the local cache is enabled")
+	check(cfg.Cache.LocalJitter >= 0 && cfg.Cache.LocalJitter < 1, "cache.local_jitter must be in [0, 1)")
+	check(cfg.Cache.WarmupRate >= 0, "cache.warmup_rate must not be negative")
+
+	check(cfg.Banners.VersionsLimit >= 1, "banners.versions_limit must be at least 1")
+
+	check(cfg.Stats.FlushInterval > 0, "stats.flush_interval must be positive")
+	check(cfg.Stats.BufferSize > 0, "stats.buffer_size must be positive")
+	check(cfg.Stats.ClicksPerMinute > 0, "stats.clicks_per_minute must be positive")
+
+	var level slog.Level
+	check(level.UnmarshalText([]byte(cfg.Log.Level)) == nil, "log.level %q must be one of debug, info, warn or error", cfg.Log.Level)
+	check(slices.Contains(logFormats, cfg.Log.Format), "log.format %q must be one of %v", cfg.Log.Format, logFormats)
+
+	check(slices.Contains(tracingExporters, cfg.Tracing.Exporter), "tracing.exporter %q must be one of %v", cfg.Tracing.Exporter, tracingExporters)
+	check(cfg.Tracing.ServiceName != "", "tracing.service_name must not be empty")
+	check(cfg.Tracing.SampleRatio >= 0 && cfg.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be in [0, 1]")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func (cfg Config) Redacted() Config {
+	redact := func(s *string) {
+		if *s != "" {
+			*s = redacted
+		}
+	}
+
+	redact(&cfg.Postgres.Password)
+	redact(&cfg.Redis.Password)
+	redact(&cfg.Auth.HMACSecret)
+	redact(&cfg.Auth.AdminPassword)
+
+	if cfg.Postgres.ConnStr != "" {
+		if u, err := url.Parse(cfg.Postgres.ConnStr); err == nil && u.User != nil {
+			if _, ok := u.User.Password(); ok {
+				u.User = url.UserPassword(u.User.Username(), redacted)
+			}
+			cfg.Postgres.ConnStr = u.String()
+		} else {
+			cfg.Postgres.ConnStr = redacted
+		}
+	}
+
+	return cfg
+}
+
+type envLoader struct {
+	errs []error
+}
+
+func (l *envLoader) lookup(name string) (string, bool) {
+	val := os.Getenv(name)
+	return val, val != ""
+}
+
+func (l *envLoader) fail(name, val string, err error) {
+	l.errs = append(l.errs, fmt.Errorf("%s=%q: %w", name, val, err))
+}
+
+func (l *envLoader) string(name string, dst *string) {
+	if val, ok := l.lookup(name); ok {
+		*dst = val
+	}
+}
+
+func (l *envLoader) int(name string, dst *int) {
+	if val, ok := l.lookup(name); ok {
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			l.fail(name, val, errors.New("not an integer"))
+			return
+		}
+		*dst = n
+	}
+}
+
+func (l *envLoader) int32(name string, dst *int32) {
+	if val, ok := l.lookup(name); ok {
+		n, err := strconv.ParseInt(val, 10, 32)
+		if err != nil {
+			l.fail(name, val, errors.New("not a 32-bit integer"))
+			return
+		}
+		*dst = int32(n)
+	}
+}
+
+func (l *envLoader) float(name string, dst *float64) {
+	if val, ok := l.lookup(name); ok {
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			l.fail(name, val, errors.New("not a number"))
+			return
+		}
+		*dst = f
+	}
+}
+
+func (l *envLoader) bool(name string, dst *bool) {
+	if val, ok := l.lookup(name); ok {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			l.fail(name, val, errors.New("not a boolean"))
+			return
+		}
+		*dst = b
+	}
+}
+
+func (l *envLoader) duration(name string, dst *time.Duration) {
+	if val, ok := l.lookup(name); ok {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			l.fail(name, val, errors.New("not a duration, expected e.g. 30s or 5m"))
+			return
+		}
+		*dst = d
+	}
+}
+
+func (l *envLoader) hostPort(hostName, portName string, dst *string) {
+	host, hostOK := l.lookup(hostName)
+	port, portOK := l.lookup(portName)
+	if !hostOK && !portOK {
+		return
+	}
+
+	curHost, curPort, err := net.SplitHostPort(*dst)
+	if err != nil {
+		curHost, curPort = *dst, ""
+	}
+	if !hostOK {
+		host = curHost
+	}
+	if !portOK {
+		port = curPort
+	}
+	if _, err := strconv.Atoi(port); err != nil {
+		l.fail(portName, port, errors.New("not a port number"))
+		return
+	}
+
+	*dst = net.JoinHostPort(host, port)
+}
@@ -0,0 +1,13 @@
+package config
+
+import "time"
+
+type HTTPConfig struct {
+	Addr               string        `yaml:"addr"`
+	ReadTimeout        time.Duration `yaml:"read_timeout"`
+	WriteTimeout       time.Duration `yaml:"write_timeout"`
+	IdleTimeout        time.Duration `yaml:"idle_timeout"`
+	DrainPeriod        time.Duration `yaml:"drain_period"`
+	ShutdownTimeout    time.Duration `yaml:"shutdown_timeout"`
+	HealthCheckTimeout time.Duration `yaml:"health_check_timeout"`
+}
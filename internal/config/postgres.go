@@ -2,19 +2,44 @@ package config
 
 import (
 	"context"
+	"net"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
 type PostgresConfig struct {
-	ConnStr         string
-	MaxConns        int32
-	MaxConnIdleTime time.Duration
+	ConnStr         string        `yaml:"conn_str"`
+	Host            string        `yaml:"host"`
+	Port            int           `yaml:"port"`
+	User            string        `yaml:"user"`
+	Password        string        `yaml:"password"`
+	Database        string        `yaml:"database"`
+	SSLMode         string        `yaml:"sslmode"`
+	MaxConns        int32         `yaml:"max_conns"`
+	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time"`
+}
+
+func (cfg PostgresConfig) DSN() string {
+	if cfg.ConnStr != "" {
+		return cfg.ConnStr
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgresql",
+		User:     url.UserPassword(cfg.User, cfg.Password),
+		Host:     net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Path:     "/" + cfg.Database,
+		RawQuery: url.Values{"sslmode": {cfg.SSLMode}}.Encode(),
+	}
+
+	return dsn.String()
 }
 
 func NewPostgresPool(cfg PostgresConfig) (*pgxpool.Pool, error) {
-	config, err := pgxpool.ParseConfig(cfg.ConnStr)
+	config, err := pgxpool.ParseConfig(cfg.DSN())
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,9 @@
+package config
+
+import "time"
+
+type StatsConfig struct {
+	FlushInterval   time.Duration `yaml:"flush_interval"`
+	BufferSize      int           `yaml:"buffer_size"`
+	ClicksPerMinute int           `yaml:"clicks_per_minute"`
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/auth"
+	"banner-service/internal/i18n"
+	"banner-service/internal/middlewares"
+	"banner-service/internal/models"
+	bannerservice "banner-service/internal/services"
+	"banner-service/internal/utils"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type AuditHandler struct {
+	auditService *bannerservice.AuditService
+	logger       *slog.Logger
+}
+
+func NewAuditHandler(service *bannerservice.AuditService, logger *slog.Logger) *AuditHandler {
+	return &AuditHandler{
+		auditService: service,
+		logger:       logger,
+	}
+}
+
+func InitAuditRoutes(auditService *bannerservice.AuditService, r *mux.Router, authMiddleware mux.MiddlewareFunc, logger *slog.Logger) {
+	ah := NewAuditHandler(auditService, logger)
+
+	s := r.PathPrefix("/auth").Subrouter()
+
+	s.Use(authMiddleware, middlewares.RequirePermission(auth.PermAuditRead))
+	s.HandleFunc("/audit", ah.GetAuditEntriesHandler).Methods("GET")
+}
+
+func (h *AuditHandler) GetAuditEntriesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	bannerID, err := utils.ParsePositiveInt(query.Get("banner_id"))
+	if err != nil {
+		invalidField(w, r, "banner_id", i18n.FieldPositiveInt)
+		return
+	}
+
+	limit, err := utils.ParsePositiveInt(query.Get("limit"))
+	if err != nil {
+		invalidField(w, r, "limit", i18n.FieldPositiveInt)
+		return
+	}
+
+	offset, err := utils.ParsePositiveInt(query.Get("offset"))
+	if err != nil {
+		invalidField(w, r, "offset", i18n.FieldPositiveInt)
+		return
+	}
+
+	filter := models.AuditFilter{
+		BannerID: bannerID,
+		Actor:    query.Get("actor"),
+		Limit:    limit,
+		Offset:   offset,
+	}
+	if fromStr := query.Get("from"); fromStr != "" {
+		if filter.From, err = time.Parse(time.RFC3339, fromStr); err != nil {
+			invalidField(w, r, "from", i18n.TimestampInvalid)
+			return
+		}
+	}
+	if toStr := query.Get("to"); toStr != "" {
+		if filter.To, err = time.Parse(time.RFC3339, toStr); err != nil {
+			invalidField(w, r, "to", i18n.TimestampInvalid)
+			return
+		}
+	}
+
+	entries, err := h.auditService.GetAuditEntries(ctx, filter)
+	if err != nil {
+		respondError(w, r, h.logger, err, "could not list audit entries", "banner_id", bannerID)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(entries)
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/auth"
+	"banner-service/internal/i18n"
+	"errors"
+	"net/http"
+)
+
+var (
+	errMissingPrincipal = errors.New("principal is missing from request context")
+	errAccessDenied     = apperrors.Forbidden(i18n.AccessDenied)
+)
+
+func authorize(w http.ResponseWriter, r *http.Request, perm auth.Permission, featureIDs ...int) (*auth.Principal, bool) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		apperrors.Write(w, r, errMissingPrincipal)
+		return nil, false
+	}
+
+	if !principal.Can(perm) {
+		apperrors.Write(w, r, errAccessDenied)
+		return nil, false
+	}
+
+	for _, featureID := range featureIDs {
+		if !principal.CanForFeature(perm, featureID) {
+			apperrors.Write(w, r, errAccessDenied)
+			return nil, false
+		}
+	}
+
+	return principal, true
+}
@@ -1,39 +1,55 @@
 package handlers
 
 import (
-	"banner-service/internal/middlewares"
+	"banner-service/internal/apperrors"
+	"banner-service/internal/auth"
+	"banner-service/internal/i18n"
 	"banner-service/internal/models"
 	bannerservice "banner-service/internal/services"
 	"banner-service/internal/utils"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
-	"github.com/jackc/pgx/v4"
+)
+
+const (
+	experimentHeader        = "X-Experiment-ID"
+	experimentVariantHeader = "X-Experiment-Variant"
 )
 
 type BannerHandler struct {
 	bannerService *bannerservice.BannerService
+	statsService  *bannerservice.StatsService
+	logger        *slog.Logger
 }
 
-func NewBannerHandler(service *bannerservice.BannerService) *BannerHandler {
+func NewBannerHandler(service *bannerservice.BannerService, statsService *bannerservice.StatsService, logger *slog.Logger) *BannerHandler {
 	return &BannerHandler{
 		bannerService: service,
+		statsService:  statsService,
+		logger:        logger,
 	}
 }
 
-func InitBannerRoutes(bannerService *bannerservice.BannerService, r *mux.Router) {
-	bh := NewBannerHandler(bannerService)
+func InitBannerRoutes(bannerService *bannerservice.BannerService, statsService *bannerservice.StatsService, r *mux.Router, authMiddleware mux.MiddlewareFunc, logger *slog.Logger) {
+	bh := NewBannerHandler(bannerService, statsService, logger)
 
 	s := r.PathPrefix("/auth").Subrouter()
 
-	s.Use(middlewares.AuthMiddleware)
+	s.Use(authMiddleware)
 	s.HandleFunc("/banners", bh.GetBannersHandler).Methods("GET")
 	s.HandleFunc("/banner", bh.GetBannerHandler).Methods("GET")
 	s.HandleFunc("/banner", bh.CreateBannerHandler).Methods("POST")
 	s.HandleFunc("/banner/{id}", bh.UpdateBannerHandler).Methods("PUT")
 	s.HandleFunc("/banner/{id}", bh.DeleteBannerHandler).Methods("DELETE")
+	s.HandleFunc("/banner/{id}/versions", bh.GetBannerVersionsHandler).Methods("GET")
+	s.HandleFunc("/banner/{id}/versions/{version}/activate", bh.ActivateBannerVersionHandler).Methods("POST")
+	s.HandleFunc("/banner/{id}/click", bh.ClickBannerHandler).Methods("POST")
+	s.HandleFunc("/banner/{id}/stats", bh.GetBannerStatsHandler).Methods("GET")
 
 }
 
@@ -44,22 +60,22 @@ func (h *BannerHandler) GetBannerHandler(w http.ResponseWriter, r *http.Request)
 	tagIDStr := r.URL.Query().Get("tag_id")
 	featureIDStr := r.URL.Query().Get("feature_id")
 	useLastRevisionStr := r.URL.Query().Get("use_last_revision")
-	isAdmin, ok := r.Context().Value("isAdminKey").(bool)
+	principal, ok := auth.PrincipalFromContext(r.Context())
 
 	if !ok {
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+		apperrors.Write(w, r, errMissingPrincipal)
 		return
 	}
 
 	tagID, err := strconv.Atoi(tagIDStr)
 	if err != nil || tagID <= 0 {
-		http.Error(w, "Некорректные данные", http.StatusBadRequest)
+		invalidField(w, r, "tag_id", i18n.FieldPositiveInt)
 		return
 	}
 
 	featureID, err := strconv.Atoi(featureIDStr)
 	if err != nil || featureID <= 0 {
-		http.Error(w, "Некорректные данные", http.StatusBadRequest)
+		invalidField(w, r, "feature_id", i18n.FieldPositiveInt)
 		return
 	}
 
@@ -68,98 +84,118 @@ func (h *BannerHandler) GetBannerHandler(w http.ResponseWriter, r *http.Request)
 		useLastRevision = false
 	}
 
-	banner, err := h.bannerService.GetBanner(ctx, tagID, featureID, useLastRevision, isAdmin)
+	isAdmin := principal.CanForFeature(auth.PermBannerRead, featureID)
+
+	banner, assignment, err := h.bannerService.GetBanner(ctx, tagID, featureID, principal.UserID, useLastRevision, isAdmin)
 	if err != nil {
-		if err.Error() == pgx.ErrNoRows.Error() {
-			http.Error(w, "Баннер для не найден", http.StatusNotFound)
-		} else {
-			println(err.Error())
-			http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
-		}
+		respondError(w, r, h.logger, err, "could not get banner", "feature_id", featureID, "tag_id", tagID)
 		return
 	}
+	if assignment != nil {
+		w.Header().Set(experimentHeader, strconv.Itoa(assignment.ExperimentID))
+		w.Header().Set(experimentVariantHeader, strconv.Itoa(assignment.BannerID))
+	}
+	if !isAdmin {
+		h.statsService.RecordImpression(banner.BannerID)
+	}
 
 	err = json.NewEncoder(w).Encode(banner)
 	if err != nil {
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+		apperrors.Write(w, r, err)
 	}
 }
 
 func (h *BannerHandler) GetBannersHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	ctx := r.Context()
-	isAdmin, ok := r.Context().Value("isAdminKey").(bool)
-
+	principal, ok := authorize(w, r, auth.PermBannerRead)
 	if !ok {
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
-		return
-	}
-
-	if !isAdmin {
-		http.Error(w, "Пользователь не имеет доступа", http.StatusForbidden)
 		return
 	}
 
 	tagID, err := utils.ParsePositiveInt(r.URL.Query().Get("tag_id"))
 	if err != nil {
-		http.Error(w, "Некорректные данные", http.StatusBadRequest)
+		invalidField(w, r, "tag_id", i18n.FieldPositiveInt)
 		return
 	}
 
 	featureID, err := utils.ParsePositiveInt(r.URL.Query().Get("feature_id"))
 	if err != nil {
-		http.Error(w, "Некорректные данные", http.StatusBadRequest)
+		invalidField(w, r, "feature_id", i18n.FieldPositiveInt)
 		return
 	}
 
 	limit, err := utils.ParsePositiveInt(r.URL.Query().Get("limit"))
 	if err != nil {
-		http.Error(w, "Некорректные данные", http.StatusBadRequest)
+		invalidField(w, r, "limit", i18n.FieldPositiveInt)
 		return
 	}
 
 	offset, err := utils.ParsePositiveInt(r.URL.Query().Get("offset"))
 	if err != nil {
-		http.Error(w, "Некорректные данные", http.StatusBadRequest)
+		invalidField(w, r, "offset", i18n.FieldPositiveInt)
 		return
 	}
 
-	banners, err := h.bannerService.GetBanners(ctx, featureID, tagID, limit, offset)
+	var activeAt *time.Time
+	if activeAtStr := r.URL.Query().Get("active_at"); activeAtStr != "" {
+		t, err := time.Parse(time.RFC3339, activeAtStr)
+		if err != nil {
+			invalidField(w, r, "active_at", i18n.TimestampInvalid)
+			return
+		}
+		activeAt = &t
+	}
+
+	if featureID > 0 && !principal.CanForFeature(auth.PermBannerRead, featureID) {
+		apperrors.Write(w, r, errAccessDenied)
+		return
+	}
+
+	banners, err := h.bannerService.GetBanners(ctx, models.BannerFilter{
+		FeatureID:  featureID,
+		TagID:      tagID,
+		FeatureIDs: principal.FeatureScope(),
+		ActiveAt:   activeAt,
+		Limit:      limit,
+		Offset:     offset,
+	})
 	if err != nil {
-		println(err.Error())
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+		respondError(w, r, h.logger, err, "could not list banners", "feature_id", featureID, "tag_id", tagID)
 		return
 	}
 
 	err = json.NewEncoder(w).Encode(banners)
 	if err != nil {
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+		apperrors.Write(w, r, err)
 	}
 }
 
 func (h *BannerHandler) CreateBannerHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	ctx := r.Context()
-	isAdmin, ok := r.Context().Value("isAdminKey").(bool)
-
-	if !ok {
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+	if _, ok := authorize(w, r, auth.PermBannerWrite); !ok {
 		return
 	}
-	if !isAdmin {
-		http.Error(w, "Пользователь не имеет доступа", http.StatusForbidden)
+	banner := models.Banner{Weight: models.DefaultBannerWeight}
+	if err := json.NewDecoder(r.Body).Decode(&banner); err != nil {
+		invalidField(w, r, "body", i18n.InvalidBody)
 		return
 	}
-	var banner models.Banner
-	if err := json.NewDecoder(r.Body).Decode(&banner); err != nil {
-		println(err.Error())
-		http.Error(w, "Некорректные данные", http.StatusBadRequest)
+
+	principal, ok := authorize(w, r, auth.PermBannerWrite, banner.FeatureID)
+	if !ok {
 		return
 	}
+	if banner.IsActive {
+		if _, ok := authorize(w, r, auth.PermBannerPublish, banner.FeatureID); !ok {
+			return
+		}
+	}
 
-	id, err := h.bannerService.CreateBanner(ctx, &banner)
+	id, err := h.bannerService.CreateBanner(ctx, &banner, principal.UserID)
 	if err != nil {
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+		respondError(w, r, h.logger, err, "could not create banner", "feature_id", banner.FeatureID)
 		return
 	}
 
@@ -170,96 +206,277 @@ func (h *BannerHandler) CreateBannerHandler(w http.ResponseWriter, r *http.Reque
 		BannerID: id,
 	})
 	if err != nil {
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+		apperrors.Write(w, r, err)
 	}
 }
 
 func (h *BannerHandler) UpdateBannerHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	ctx := r.Context()
-	isAdmin, ok := r.Context().Value("isAdminKey").(bool)
-
-	if !ok {
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
-		return
-	}
-	if !isAdmin {
-		http.Error(w, "Пользователь не имеет доступа", http.StatusForbidden)
+	if _, ok := authorize(w, r, auth.PermBannerWrite); !ok {
 		return
 	}
 
 	vars := mux.Vars(r)
 	bannerIDStr, ok := vars["id"]
 	if !ok {
-		http.Error(w, "Некорректные данные", http.StatusBadRequest)
+		invalidField(w, r, "id", i18n.FieldPositiveInt)
 		return
 	}
 	bannerID, err := strconv.Atoi(bannerIDStr)
 	if err != nil {
-		http.Error(w, "Некорректные данные", http.StatusBadRequest)
+		invalidField(w, r, "id", i18n.FieldPositiveInt)
 		return
 	}
 
-	var banner models.Banner
+	banner := models.Banner{Weight: models.DefaultBannerWeight}
 	if err := json.NewDecoder(r.Body).Decode(&banner); err != nil {
-		http.Error(w, "Некорректные данные", http.StatusBadRequest)
+		invalidField(w, r, "body", i18n.InvalidBody)
 		return
 	}
 
-	if err := h.bannerService.UpdateBanner(ctx, bannerID, &banner); err != nil {
-		if err.Error() == "no rows affected" {
-			http.Error(w, "Баннер не найден", http.StatusNotFound)
-			return
-		}
-		if err.Error() == "ERROR: Not a unique combination of tag_id and feature_id (SQLSTATE P0001)" {
-			http.Error(w, "Некорректные данные", http.StatusBadRequest)
+	current, ok := h.getBannerByID(w, r, bannerID)
+	if !ok {
+		return
+	}
+
+	principal, ok := authorize(w, r, auth.PermBannerWrite, current.FeatureID, banner.FeatureID)
+	if !ok {
+		return
+	}
+	if banner.IsActive != current.IsActive || (banner.IsActive && banner.FeatureID != current.FeatureID) {
+		if _, ok := authorize(w, r, auth.PermBannerPublish, current.FeatureID, banner.FeatureID); !ok {
 			return
 		}
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
-		println(err.Error())
+	}
+
+	if err := h.bannerService.UpdateBanner(ctx, bannerID, &banner, principal.UserID); err != nil {
+		respondError(w, r, h.logger, err, "could not update banner", "banner_id", bannerID)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write([]byte("OK")); err != nil {
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+		apperrors.Write(w, r, err)
 	}
 }
 
 func (h *BannerHandler) DeleteBannerHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	ctx := r.Context()
-	isAdmin, ok := r.Context().Value("isAdminKey").(bool)
+	if _, ok := authorize(w, r, auth.PermBannerDelete); !ok {
+		return
+	}
 
+	vars := mux.Vars(r)
+	bannerIDStr, ok := vars["id"]
 	if !ok {
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+		invalidField(w, r, "id", i18n.FieldPositiveInt)
 		return
 	}
-	if !isAdmin {
-		http.Error(w, "Пользователь не имеет доступа", http.StatusForbidden)
+	bannerID, err := strconv.Atoi(bannerIDStr)
+	if err != nil {
+		invalidField(w, r, "id", i18n.FieldPositiveInt)
 		return
 	}
 
-	vars := mux.Vars(r)
-	bannerIDStr, ok := vars["id"]
+	current, ok := h.getBannerByID(w, r, bannerID)
 	if !ok {
-		http.Error(w, "Некорректные данные", http.StatusBadRequest)
 		return
 	}
-	bannerID, err := strconv.Atoi(bannerIDStr)
+	principal, ok := authorize(w, r, auth.PermBannerDelete, current.FeatureID)
+	if !ok {
+		return
+	}
+
+	if err := h.bannerService.DeleteBanner(ctx, bannerID, principal.UserID); err != nil {
+		respondError(w, r, h.logger, err, "could not delete banner", "banner_id", bannerID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BannerHandler) GetBannerVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	if _, ok := authorize(w, r, auth.PermBannerRead); !ok {
+		return
+	}
+
+	bannerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || bannerID <= 0 {
+		invalidField(w, r, "id", i18n.FieldPositiveInt)
+		return
+	}
+
+	current, ok := h.getBannerByID(w, r, bannerID)
+	if !ok {
+		return
+	}
+	if _, ok := authorize(w, r, auth.PermBannerRead, current.FeatureID); !ok {
+		return
+	}
+
+	versions, err := h.bannerService.GetBannerVersions(ctx, bannerID)
 	if err != nil {
-		http.Error(w, "Некорректные данные", http.StatusBadRequest)
+		respondError(w, r, h.logger, err, "could not list banner versions", "banner_id", bannerID)
 		return
 	}
 
-	if err := h.bannerService.DeleteBanner(ctx, bannerID); err != nil {
-		if err.Error() == "no rows affected" {
-			http.Error(w, "Баннер не найден", http.StatusNotFound)
-			return
+	err = json.NewEncoder(w).Encode(versions)
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *BannerHandler) ActivateBannerVersionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	if _, ok := authorize(w, r, auth.PermBannerPublish); !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	bannerID, err := strconv.Atoi(vars["id"])
+	if err != nil || bannerID <= 0 {
+		invalidField(w, r, "id", i18n.FieldPositiveInt)
+		return
+	}
+	version, err := strconv.Atoi(vars["version"])
+	if err != nil || version <= 0 {
+		invalidField(w, r, "version", i18n.FieldPositiveInt)
+		return
+	}
+
+	versions, err := h.bannerService.GetBannerVersions(ctx, bannerID)
+	if err != nil {
+		respondError(w, r, h.logger, err, "could not activate banner version", "banner_id", bannerID, "version", version)
+		return
+	}
+
+	current, ok := h.getBannerByID(w, r, bannerID)
+	if !ok {
+		return
+	}
+	featureIDs := []int{current.FeatureID}
+	for _, v := range versions {
+		if v.Version == version {
+			featureIDs = append(featureIDs, v.FeatureID)
 		}
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+	}
+
+	principal, ok := authorize(w, r, auth.PermBannerPublish, featureIDs...)
+	if !ok {
+		return
+	}
+
+	if err := h.bannerService.ActivateBannerVersion(ctx, bannerID, version, principal.UserID); err != nil {
+		respondError(w, r, h.logger, err, "could not activate banner version", "banner_id", bannerID, "version", version)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("OK")); err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *BannerHandler) ClickBannerHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		apperrors.Write(w, r, errMissingPrincipal)
+		return
+	}
+
+	bannerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || bannerID <= 0 {
+		invalidField(w, r, "id", i18n.FieldPositiveInt)
+		return
+	}
+
+	featureID, err := strconv.Atoi(r.URL.Query().Get("feature_id"))
+	if err != nil || featureID <= 0 {
+		invalidField(w, r, "feature_id", i18n.FieldPositiveInt)
+		return
+	}
+
+	tagID, err := strconv.Atoi(r.URL.Query().Get("tag_id"))
+	if err != nil || tagID <= 0 {
+		invalidField(w, r, "tag_id", i18n.FieldPositiveInt)
+		return
+	}
+
+	if err := h.statsService.AllowClick(principal.UserID); err != nil {
+		respondError(w, r, h.logger, err, "could not record click", "banner_id", bannerID)
 		return
 	}
 
+	if err := h.bannerService.CheckBannerServed(ctx, featureID, tagID, bannerID); err != nil {
+		respondError(w, r, h.logger, err, "could not record click", "banner_id", bannerID, "feature_id", featureID, "tag_id", tagID)
+		return
+	}
+
+	h.statsService.RecordClick(bannerID)
+
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *BannerHandler) GetBannerStatsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	if _, ok := authorize(w, r, auth.PermBannerRead); !ok {
+		return
+	}
+
+	bannerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || bannerID <= 0 {
+		invalidField(w, r, "id", i18n.FieldPositiveInt)
+		return
+	}
+
+	var from, to time.Time
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		if from, err = time.Parse(time.RFC3339, fromStr); err != nil {
+			invalidField(w, r, "from", i18n.TimestampInvalid)
+			return
+		}
+	}
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		if to, err = time.Parse(time.RFC3339, toStr); err != nil {
+			invalidField(w, r, "to", i18n.TimestampInvalid)
+			return
+		}
+	}
+
+	current, ok := h.getBannerByID(w, r, bannerID)
+	if !ok {
+		return
+	}
+	if _, ok := authorize(w, r, auth.PermBannerRead, current.FeatureID); !ok {
+		return
+	}
+
+	stats, err := h.statsService.GetBannerStats(ctx, bannerID, from, to, r.URL.Query().Get("granularity"))
+	if err != nil {
+		respondError(w, r, h.logger, err, "could not get banner stats", "banner_id", bannerID)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(stats)
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *BannerHandler) getBannerByID(w http.ResponseWriter, r *http.Request, bannerID int) (*models.Banner, bool) {
+	banner, err := h.bannerService.GetBannerByID(r.Context(), bannerID)
+	if err != nil {
+		respondError(w, r, h.logger, err, "could not get banner", "banner_id", bannerID)
+		return nil, false
+	}
+
+	return banner, true
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/auth"
+	"banner-service/internal/i18n"
+	"banner-service/internal/middlewares"
+	bannerservice "banner-service/internal/services"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+var errWarmupRunning = apperrors.Conflict(i18n.WarmupRunning, nil)
+
+type CacheHandler struct {
+	warmupService *bannerservice.WarmupService
+}
+
+func NewCacheHandler(service *bannerservice.WarmupService) *CacheHandler {
+	return &CacheHandler{
+		warmupService: service,
+	}
+}
+
+func InitCacheRoutes(warmupService *bannerservice.WarmupService, r *mux.Router, authMiddleware mux.MiddlewareFunc) {
+	ch := NewCacheHandler(warmupService)
+
+	s := r.PathPrefix("/auth").Subrouter()
+
+	s.Use(authMiddleware, middlewares.RequirePermission(auth.PermCacheManage))
+	s.HandleFunc("/cache/warmup", ch.StartWarmupHandler).Methods("POST")
+	s.HandleFunc("/cache/warmup", ch.GetWarmupHandler).Methods("GET")
+}
+
+func (h *CacheHandler) StartWarmupHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if !h.warmupService.Start(context.WithoutCancel(r.Context())) {
+		apperrors.Write(w, r, errWarmupRunning)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	err := json.NewEncoder(w).Encode(h.warmupService.Status())
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *CacheHandler) GetWarmupHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(h.warmupService.Status())
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
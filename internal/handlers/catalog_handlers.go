@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/auth"
+	"banner-service/internal/i18n"
+	"banner-service/internal/models"
+	"banner-service/internal/utils"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type catalogHandler[T any] struct {
+	name   string
+	logger *slog.Logger
+	list   func(ctx context.Context, limit, offset int) ([]*T, error)
+	get    func(ctx context.Context, id int) (*T, error)
+	create func(ctx context.Context, item *T) (int, error)
+	update func(ctx context.Context, id int, item *T) error
+	delete func(ctx context.Context, id int, dryRun bool, author string) (*models.DeletePreview, error)
+}
+
+func (h *catalogHandler[T]) idKey() string {
+	return h.name + "_id"
+}
+
+func (h *catalogHandler[T]) listHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	limit, err := utils.ParsePositiveInt(r.URL.Query().Get("limit"))
+	if err != nil {
+		invalidField(w, r, "limit", i18n.FieldPositiveInt)
+		return
+	}
+
+	offset, err := utils.ParsePositiveInt(r.URL.Query().Get("offset"))
+	if err != nil {
+		invalidField(w, r, "offset", i18n.FieldPositiveInt)
+		return
+	}
+
+	items, err := h.list(ctx, limit, offset)
+	if err != nil {
+		respondError(w, r, h.logger, err, "could not list "+h.name+"s")
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(items)
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *catalogHandler[T]) getHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	id, ok := catalogID(w, r)
+	if !ok {
+		return
+	}
+
+	item, err := h.get(ctx, id)
+	if err != nil {
+		respondError(w, r, h.logger, err, "could not get "+h.name, h.idKey(), id)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(item)
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *catalogHandler[T]) createHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	var item T
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		invalidField(w, r, "body", i18n.InvalidBody)
+		return
+	}
+
+	id, err := h.create(ctx, &item)
+	if err != nil {
+		respondError(w, r, h.logger, err, "could not create "+h.name)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(map[string]int{h.idKey(): id})
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *catalogHandler[T]) updateHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	id, ok := catalogID(w, r)
+	if !ok {
+		return
+	}
+
+	var item T
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		invalidField(w, r, "body", i18n.InvalidBody)
+		return
+	}
+
+	if err := h.update(ctx, id, &item); err != nil {
+		respondError(w, r, h.logger, err, "could not update "+h.name, h.idKey(), id)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("OK")); err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *catalogHandler[T]) deleteHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	id, ok := catalogID(w, r)
+	if !ok {
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+	if err != nil {
+		dryRun = false
+	}
+
+	principal, ok := authorize(w, r, auth.PermCatalogManage)
+	if !ok {
+		return
+	}
+
+	preview, err := h.delete(ctx, id, dryRun, principal.UserID)
+	if err != nil {
+		respondError(w, r, h.logger, err, "could not delete "+h.name, h.idKey(), id)
+		return
+	}
+
+	if !dryRun {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(preview)
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func catalogID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		invalidField(w, r, "id", i18n.FieldPositiveInt)
+		return 0, false
+	}
+
+	return id, true
+}
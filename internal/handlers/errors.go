@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/i18n"
+	"log/slog"
+	"net/http"
+)
+
+func respondError(w http.ResponseWriter, r *http.Request, logger *slog.Logger, err error, msg string, args ...any) {
+	if !apperrors.IsExpected(err) {
+		logger.ErrorContext(r.Context(), msg, append(args, "error", err)...)
+	}
+
+	apperrors.Write(w, r, err)
+}
+
+func invalidField(w http.ResponseWriter, r *http.Request, field string, message i18n.Message) {
+	apperrors.Write(w, r, apperrors.InvalidField(field, message))
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/auth"
+	"banner-service/internal/i18n"
+	"banner-service/internal/models"
+	bannerservice "banner-service/internal/services"
+	"banner-service/internal/utils"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type ExperimentHandler struct {
+	experimentService *bannerservice.ExperimentService
+	logger            *slog.Logger
+}
+
+func NewExperimentHandler(service *bannerservice.ExperimentService, logger *slog.Logger) *ExperimentHandler {
+	return &ExperimentHandler{
+		experimentService: service,
+		logger:            logger,
+	}
+}
+
+func InitExperimentRoutes(experimentService *bannerservice.ExperimentService, r *mux.Router, authMiddleware mux.MiddlewareFunc, logger *slog.Logger) {
+	eh := NewExperimentHandler(experimentService, logger)
+
+	s := r.PathPrefix("/auth").Subrouter()
+
+	s.Use(authMiddleware)
+	s.HandleFunc("/experiments", eh.GetExperimentsHandler).Methods("GET")
+	s.HandleFunc("/experiments", eh.CreateExperimentHandler).Methods("POST")
+	s.HandleFunc("/experiments/{id}", eh.GetExperimentHandler).Methods("GET")
+	s.HandleFunc("/experiments/{id}/pause", eh.PauseExperimentHandler).Methods("POST")
+	s.HandleFunc("/experiments/{id}/resume", eh.ResumeExperimentHandler).Methods("POST")
+	s.HandleFunc("/experiments/{id}/conclude", eh.ConcludeExperimentHandler).Methods("POST")
+}
+
+func (h *ExperimentHandler) GetExperimentsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	principal, ok := authorize(w, r, auth.PermBannerRead)
+	if !ok {
+		return
+	}
+
+	limit, err := utils.ParsePositiveInt(r.URL.Query().Get("limit"))
+	if err != nil {
+		invalidField(w, r, "limit", i18n.FieldPositiveInt)
+		return
+	}
+
+	offset, err := utils.ParsePositiveInt(r.URL.Query().Get("offset"))
+	if err != nil {
+		invalidField(w, r, "offset", i18n.FieldPositiveInt)
+		return
+	}
+
+	experiments, err := h.experimentService.GetExperiments(ctx, principal.FeatureScope(), limit, offset)
+	if err != nil {
+		respondError(w, r, h.logger, err, "could not list experiments")
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(experiments)
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *ExperimentHandler) GetExperimentHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	experiment, _, ok := h.getExperiment(w, r, auth.PermBannerRead)
+	if !ok {
+		return
+	}
+
+	err := json.NewEncoder(w).Encode(experiment)
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *ExperimentHandler) CreateExperimentHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	if _, ok := authorize(w, r, auth.PermBannerPublish); !ok {
+		return
+	}
+
+	var experiment models.Experiment
+	if err := json.NewDecoder(r.Body).Decode(&experiment); err != nil {
+		invalidField(w, r, "body", i18n.InvalidBody)
+		return
+	}
+
+	principal, ok := authorize(w, r, auth.PermBannerPublish, experiment.FeatureID)
+	if !ok {
+		return
+	}
+
+	id, err := h.experimentService.CreateExperiment(ctx, &experiment, principal.UserID)
+	if err != nil {
+		respondError(w, r, h.logger, err, "could not create experiment", "feature_id", experiment.FeatureID, "tag_id", experiment.TagID)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(struct {
+		ExperimentID int `json:"experiment_id"`
+	}{
+		ExperimentID: id,
+	})
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *ExperimentHandler) PauseExperimentHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, func(principal *auth.Principal, experimentID int) error {
+		return h.experimentService.PauseExperiment(r.Context(), experimentID, principal.UserID)
+	})
+}
+
+func (h *ExperimentHandler) ResumeExperimentHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, func(principal *auth.Principal, experimentID int) error {
+		return h.experimentService.ResumeExperiment(r.Context(), experimentID, principal.UserID)
+	})
+}
+
+func (h *ExperimentHandler) ConcludeExperimentHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, func(principal *auth.Principal, experimentID int) error {
+		var body struct {
+			WinnerBannerID int `json:"winner_banner_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			return apperrors.InvalidField("body", i18n.InvalidBody)
+		}
+
+		return h.experimentService.ConcludeExperiment(r.Context(), experimentID, body.WinnerBannerID, principal.UserID)
+	})
+}
+
+func (h *ExperimentHandler) changeStatus(w http.ResponseWriter, r *http.Request, change func(principal *auth.Principal, experimentID int) error) {
+	w.Header().Set("Content-Type", "application/json")
+	experiment, principal, ok := h.getExperiment(w, r, auth.PermBannerPublish)
+	if !ok {
+		return
+	}
+
+	if err := change(principal, experiment.ExperimentID); err != nil {
+		respondError(w, r, h.logger, err, "could not change experiment status", "experiment_id", experiment.ExperimentID)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("OK")); err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *ExperimentHandler) getExperiment(w http.ResponseWriter, r *http.Request, perm auth.Permission) (*models.Experiment, *auth.Principal, bool) {
+	experimentID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || experimentID <= 0 {
+		invalidField(w, r, "id", i18n.FieldPositiveInt)
+		return nil, nil, false
+	}
+
+	experiment, err := h.experimentService.GetExperiment(r.Context(), experimentID)
+	if err != nil {
+		respondError(w, r, h.logger, err, "could not get experiment", "experiment_id", experimentID)
+		return nil, nil, false
+	}
+
+	principal, ok := authorize(w, r, perm, experiment.FeatureID)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return experiment, principal, true
+}
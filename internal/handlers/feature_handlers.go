@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/auth"
+	"banner-service/internal/i18n"
+	"banner-service/internal/middlewares"
+	"banner-service/internal/models"
+	bannerservice "banner-service/internal/services"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type FeatureHandler struct {
+	catalogHandler[models.Feature]
+	featureService *bannerservice.FeatureService
+}
+
+func NewFeatureHandler(service *bannerservice.FeatureService, logger *slog.Logger) *FeatureHandler {
+	return &FeatureHandler{
+		catalogHandler: catalogHandler[models.Feature]{
+			name:   "feature",
+			logger: logger,
+			list:   service.GetFeatures,
+			get:    service.GetFeature,
+			create: service.CreateFeature,
+			update: service.UpdateFeature,
+			delete: service.DeleteFeature,
+		},
+		featureService: service,
+	}
+}
+
+func InitFeatureRoutes(featureService *bannerservice.FeatureService, r *mux.Router, authMiddleware mux.MiddlewareFunc, logger *slog.Logger) {
+	fh := NewFeatureHandler(featureService, logger)
+
+	s := r.PathPrefix("/auth").Subrouter()
+
+	s.Use(authMiddleware, middlewares.RequirePermission(auth.PermCatalogManage))
+	s.HandleFunc("/features", fh.listHandler).Methods("GET")
+	s.HandleFunc("/features", fh.createHandler).Methods("POST")
+	s.HandleFunc("/features/{id}", fh.getHandler).Methods("GET")
+	s.HandleFunc("/features/{id}", fh.updateHandler).Methods("PUT")
+	s.HandleFunc("/features/{id}", fh.deleteHandler).Methods("DELETE")
+	s.HandleFunc("/features/{id}/cache-policy", fh.SetFeatureCachePolicyHandler).Methods("PUT")
+	s.HandleFunc("/features/{id}/rotation-mode", fh.SetFeatureRotationModeHandler).Methods("PUT")
+}
+
+func (h *FeatureHandler) SetFeatureCachePolicyHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	featureID, ok := catalogID(w, r)
+	if !ok {
+		return
+	}
+
+	var feature models.Feature
+	if err := json.NewDecoder(r.Body).Decode(&feature); err != nil {
+		invalidField(w, r, "body", i18n.InvalidBody)
+		return
+	}
+
+	if err := h.featureService.SetFeatureCachePolicy(ctx, featureID, feature.CachePolicy, feature.CacheTTLSeconds); err != nil {
+		respondError(w, r, h.logger, err, "could not set feature cache policy", "feature_id", featureID)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("OK")); err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *FeatureHandler) SetFeatureRotationModeHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	featureID, ok := catalogID(w, r)
+	if !ok {
+		return
+	}
+
+	var feature models.Feature
+	if err := json.NewDecoder(r.Body).Decode(&feature); err != nil {
+		invalidField(w, r, "body", i18n.InvalidBody)
+		return
+	}
+
+	if err := h.featureService.SetFeatureRotationMode(ctx, featureID, feature.RotationMode); err != nil {
+		respondError(w, r, h.logger, err, "could not set feature rotation mode", "feature_id", featureID)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("OK")); err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/models"
+	bannerservice "banner-service/internal/services"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type HealthHandler struct {
+	healthService *bannerservice.HealthService
+}
+
+func NewHealthHandler(service *bannerservice.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: service,
+	}
+}
+
+func InitHealthRoutes(healthService *bannerservice.HealthService, r *mux.Router) {
+	hh := NewHealthHandler(healthService)
+
+	r.HandleFunc("/healthz", hh.LiveHandler).Methods("GET")
+	r.HandleFunc("/readyz", hh.ReadyHandler).Methods("GET")
+}
+
+func (h *HealthHandler) LiveHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(models.HealthReport{Status: models.HealthStatusOK})
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *HealthHandler) ReadyHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+
+	report := h.healthService.Check(ctx)
+	switch report.Status {
+	case models.HealthStatusOK, models.HealthStatusDegraded:
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	err := json.NewEncoder(w).Encode(report)
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
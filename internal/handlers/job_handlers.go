@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/auth"
+	"banner-service/internal/i18n"
+	bannerservice "banner-service/internal/services"
+	"banner-service/internal/utils"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+var errJobNotFound = apperrors.NotFound(i18n.JobNotFound)
+
+type JobHandler struct {
+	jobService *bannerservice.JobService
+	logger     *slog.Logger
+}
+
+func NewJobHandler(service *bannerservice.JobService, logger *slog.Logger) *JobHandler {
+	return &JobHandler{
+		jobService: service,
+		logger:     logger,
+	}
+}
+
+func InitJobRoutes(jobService *bannerservice.JobService, r *mux.Router, authMiddleware mux.MiddlewareFunc, logger *slog.Logger) {
+	jh := NewJobHandler(jobService, logger)
+
+	s := r.PathPrefix("/auth").Subrouter()
+
+	s.Use(authMiddleware)
+	s.HandleFunc("/banner", jh.DeleteBannersHandler).Methods("DELETE")
+	s.HandleFunc("/jobs/{id}", jh.GetJobHandler).Methods("GET")
+}
+
+func (h *JobHandler) DeleteBannersHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	if _, ok := authorize(w, r, auth.PermBannerDelete); !ok {
+		return
+	}
+
+	featureID, err := utils.ParsePositiveInt(r.URL.Query().Get("feature_id"))
+	if err != nil {
+		invalidField(w, r, "feature_id", i18n.FieldPositiveInt)
+		return
+	}
+
+	tagID, err := utils.ParsePositiveInt(r.URL.Query().Get("tag_id"))
+	if err != nil {
+		invalidField(w, r, "tag_id", i18n.FieldPositiveInt)
+		return
+	}
+
+	if featureID <= 0 && tagID <= 0 {
+		apperrors.Write(w, r, bannerservice.ErrDeletionFilterRequired)
+		return
+	}
+
+	principal, ok := authorize(w, r, auth.PermBannerDelete, featureID)
+	if !ok {
+		return
+	}
+
+	jobID, err := h.jobService.ScheduleBannersDeletion(ctx, featureID, tagID, principal.UserID)
+	if err != nil {
+		respondError(w, r, h.logger, err, "could not schedule banner deletion", "feature_id", featureID, "tag_id", tagID)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	err = json.NewEncoder(w).Encode(struct {
+		JobID int `json:"job_id"`
+	}{
+		JobID: jobID,
+	})
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *JobHandler) GetJobHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	principal, ok := authorize(w, r, auth.PermBannerDelete)
+	if !ok {
+		return
+	}
+
+	jobID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || jobID <= 0 {
+		invalidField(w, r, "id", i18n.FieldPositiveInt)
+		return
+	}
+
+	job, err := h.jobService.GetJob(ctx, jobID)
+	if err != nil {
+		respondError(w, r, h.logger, err, "could not get job", "job_id", jobID)
+		return
+	}
+
+	if !principal.CanForFeature(auth.PermBannerDelete, job.FeatureID) {
+		apperrors.Write(w, r, errJobNotFound)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(job)
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"banner-service/internal/auth"
+	"banner-service/internal/middlewares"
+	"banner-service/internal/models"
+	bannerservice "banner-service/internal/services"
+	"log/slog"
+
+	"github.com/gorilla/mux"
+)
+
+type TagHandler struct {
+	catalogHandler[models.Tag]
+}
+
+func NewTagHandler(service *bannerservice.TagService, logger *slog.Logger) *TagHandler {
+	return &TagHandler{
+		catalogHandler: catalogHandler[models.Tag]{
+			name:   "tag",
+			logger: logger,
+			list:   service.GetTags,
+			get:    service.GetTag,
+			create: service.CreateTag,
+			update: service.UpdateTag,
+			delete: service.DeleteTag,
+		},
+	}
+}
+
+func InitTagRoutes(tagService *bannerservice.TagService, r *mux.Router, authMiddleware mux.MiddlewareFunc, logger *slog.Logger) {
+	th := NewTagHandler(tagService, logger)
+
+	s := r.PathPrefix("/auth").Subrouter()
+
+	s.Use(authMiddleware, middlewares.RequirePermission(auth.PermCatalogManage))
+	s.HandleFunc("/tags", th.listHandler).Methods("GET")
+	s.HandleFunc("/tags", th.createHandler).Methods("POST")
+	s.HandleFunc("/tags/{id}", th.getHandler).Methods("GET")
+	s.HandleFunc("/tags/{id}", th.updateHandler).Methods("PUT")
+	s.HandleFunc("/tags/{id}", th.deleteHandler).Methods("DELETE")
+}
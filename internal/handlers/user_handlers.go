@@ -1,54 +1,113 @@
 package handlers
 
 import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/auth"
+	"banner-service/internal/i18n"
+	"banner-service/internal/middlewares"
+	"banner-service/internal/models"
+	bannerservice "banner-service/internal/services"
 	"encoding/json"
+	"log/slog"
+	"net"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/gorilla/mux"
 )
 
-func InitUserRoutes(r *mux.Router) {
+type UserHandler struct {
+	userService *bannerservice.UserService
+	signer      *auth.Signer
+	logger      *slog.Logger
+}
+
+func NewUserHandler(service *bannerservice.UserService, signer *auth.Signer, logger *slog.Logger) *UserHandler {
+	return &UserHandler{
+		userService: service,
+		signer:      signer,
+		logger:      logger,
+	}
+}
+
+func InitUserRoutes(userService *bannerservice.UserService, signer *auth.Signer, devMode bool, r *mux.Router, authMiddleware mux.MiddlewareFunc, logger *slog.Logger) {
+	uh := NewUserHandler(userService, signer, logger)
 
 	s := r.PathPrefix("/").Subrouter()
 
-	s.HandleFunc("/token", GetTokenHandler).Methods("GET")
-	s.HandleFunc("/admin-token", GetAdminTokenHandler).Methods("GET")
+	if signer != nil {
+		s.HandleFunc("/login", uh.LoginHandler).Methods("POST")
+	}
+	if signer != nil && devMode {
+		s.HandleFunc("/token", uh.GetTokenHandler).Methods("GET")
+		s.HandleFunc("/admin-token", uh.GetAdminTokenHandler).Methods("GET")
+	}
+
+	a := r.PathPrefix("/auth").Subrouter()
 
+	a.Use(authMiddleware, middlewares.RequirePermission(auth.PermUserManage))
+	a.HandleFunc("/users", uh.CreateUserHandler).Methods("POST")
+	a.HandleFunc("/users/{id}/token", uh.IssueUserTokenHandler).Methods("POST")
+	a.HandleFunc("/users/{id}/token", uh.RevokeUserTokenHandler).Methods("DELETE")
+	a.HandleFunc("/users/{id}/role", uh.SetUserRoleHandler).Methods("PUT")
 }
 
-func GetTokenHandler(w http.ResponseWriter, r *http.Request) {
+func (h *UserHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	token := jwt.New(jwt.SigningMethodHS256)
+	ctx := r.Context()
 
-	claims := token.Claims.(jwt.MapClaims)
-	claims["admin"] = false
-	claims["exp"] = time.Now().Add(time.Minute * 15).Unix()
+	var credentials struct {
+		Login    string `json:"login"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
+		invalidField(w, r, "body", i18n.InvalidBody)
+		return
+	}
+	if credentials.Login == "" || credentials.Password == "" {
+		apperrors.Write(w, r, apperrors.Validation(i18n.InvalidRequest,
+			apperrors.FieldError{Field: "login", Message: i18n.FieldRequired},
+			apperrors.FieldError{Field: "password", Message: i18n.FieldRequired},
+		))
+		return
+	}
 
-	tokenString, err := token.SignedString([]byte("secret"))
+	tokenString, err := h.userService.Login(ctx, credentials.Login, credentials.Password, clientIP(r))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondError(w, r, h.logger, err, "could not log in")
 		return
 	}
-	response := map[string]string{
+
+	err = json.NewEncoder(w).Encode(map[string]string{
 		"token": tokenString,
+	})
+	if err != nil {
+		apperrors.Write(w, r, err)
 	}
+}
+
+func (h *UserHandler) GetTokenHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
+
+	tokenString, err := h.signer.Sign(jwt.MapClaims{"admin": false}, time.Minute*15)
+	if err != nil {
+		respondError(w, r, h.logger, err, "could not sign token")
+		return
+	}
+	response := map[string]string{
+		"token": tokenString,
+	}
 	json.NewEncoder(w).Encode(response)
 }
 
-func GetAdminTokenHandler(w http.ResponseWriter, r *http.Request) {
+func (h *UserHandler) GetAdminTokenHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	token := jwt.New(jwt.SigningMethodHS256)
-
-	claims := token.Claims.(jwt.MapClaims)
-	claims["admin"] = true
-	claims["exp"] = time.Now().Add(time.Minute * 10).Unix()
 
-	tokenString, err := token.SignedString([]byte("secret"))
+	tokenString, err := h.signer.Sign(jwt.MapClaims{"admin": true}, time.Minute*10)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondError(w, r, h.logger, err, "could not sign token")
 		return
 	}
 	response := map[string]string{
@@ -57,3 +116,122 @@ func GetAdminTokenHandler(w http.ResponseWriter, r *http.Request) {
 
 	json.NewEncoder(w).Encode(response)
 }
+
+func (h *UserHandler) CreateUserHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	var request struct {
+		Login      string `json:"login"`
+		Password   string `json:"password"`
+		IsAdmin    bool   `json:"is_admin"`
+		Role       string `json:"role"`
+		FeatureIDs []int  `json:"feature_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		invalidField(w, r, "body", i18n.InvalidBody)
+		return
+	}
+
+	user := &models.User{
+		Login:      request.Login,
+		IsAdmin:    request.IsAdmin,
+		Role:       request.Role,
+		FeatureIDs: request.FeatureIDs,
+	}
+	userID, token, err := h.userService.CreateUser(ctx, user, request.Password)
+	if err != nil {
+		respondError(w, r, h.logger, err, "could not create user")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(struct {
+		UserID int    `json:"user_id"`
+		Token  string `json:"token"`
+	}{
+		UserID: userID,
+		Token:  token,
+	})
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *UserHandler) IssueUserTokenHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || userID <= 0 {
+		invalidField(w, r, "id", i18n.FieldPositiveInt)
+		return
+	}
+
+	token, err := h.userService.IssueToken(ctx, userID)
+	if err != nil {
+		respondError(w, r, h.logger, err, "could not issue user token", "user_id", userID)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(map[string]string{
+		"token": token,
+	})
+	if err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func (h *UserHandler) RevokeUserTokenHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || userID <= 0 {
+		invalidField(w, r, "id", i18n.FieldPositiveInt)
+		return
+	}
+
+	if err := h.userService.RevokeToken(ctx, userID); err != nil {
+		respondError(w, r, h.logger, err, "could not revoke user token", "user_id", userID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) SetUserRoleHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ctx := r.Context()
+
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || userID <= 0 {
+		invalidField(w, r, "id", i18n.FieldPositiveInt)
+		return
+	}
+
+	var request struct {
+		Role       string `json:"role"`
+		FeatureIDs []int  `json:"feature_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		invalidField(w, r, "body", i18n.InvalidBody)
+		return
+	}
+
+	if err := h.userService.SetUserRole(ctx, userID, request.Role, request.FeatureIDs); err != nil {
+		respondError(w, r, h.logger, err, "could not set user role", "user_id", userID)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("OK")); err != nil {
+		apperrors.Write(w, r, err)
+	}
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
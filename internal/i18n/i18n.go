@@ -0,0 +1,42 @@
+package i18n
+
+import (
+	"net/http"
+
+	"golang.org/x/text/language"
+)
+
+type Message string
+
+const (
+	Russian = "ru"
+	English = "en"
+
+	DefaultLanguage = Russian
+)
+
+var matcher = language.NewMatcher([]language.Tag{language.Russian, language.English})
+
+func Negotiate(acceptLanguage string) string {
+	tag, _ := language.MatchStrings(matcher, acceptLanguage)
+	if base, _ := tag.Base(); base.String() == English {
+		return English
+	}
+
+	return DefaultLanguage
+}
+
+func FromRequest(r *http.Request) string {
+	return Negotiate(r.Header.Get("Accept-Language"))
+}
+
+func Translate(lang string, msg Message) string {
+	if text, ok := catalog[lang][msg]; ok {
+		return text
+	}
+	if text, ok := catalog[DefaultLanguage][msg]; ok {
+		return text
+	}
+
+	return string(msg)
+}
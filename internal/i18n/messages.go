@@ -0,0 +1,167 @@
+package i18n
+
+const (
+	InternalError   Message = "internal_error"
+	InvalidRequest  Message = "invalid_request"
+	Unauthenticated Message = "unauthenticated"
+	AccessDenied    Message = "access_denied"
+
+	InvalidCredentials Message = "invalid_credentials"
+	TokensDisabled     Message = "tokens_disabled"
+	WarmupRunning      Message = "warmup_running"
+	TooManyRequests    Message = "too_many_requests"
+
+	BannerNotFound        Message = "banner_not_found"
+	BannerVersionNotFound Message = "banner_version_not_found"
+	FeatureNotFound       Message = "feature_not_found"
+	TagNotFound           Message = "tag_not_found"
+	JobNotFound           Message = "job_not_found"
+	UserNotFound          Message = "user_not_found"
+
+	BannerCombinationExists Message = "banner_combination_exists"
+	UserExists              Message = "user_exists"
+
+	FieldRequired        Message = "field_required"
+	FieldPositiveInt     Message = "field_positive_int"
+	InvalidBody          Message = "invalid_body"
+	FeatureIDInvalid     Message = "feature_id_invalid"
+	FeatureMissing       Message = "feature_missing"
+	TagIDsRequired       Message = "tag_ids_required"
+	TagIDsInvalid        Message = "tag_ids_invalid"
+	TagMissing           Message = "tag_missing"
+	ContentInvalid       Message = "content_invalid"
+	VersionInvalid       Message = "version_invalid"
+	CachePolicyInvalid   Message = "cache_policy_invalid"
+	NameInvalid          Message = "name_invalid"
+	LoginInvalid         Message = "login_invalid"
+	PasswordTooShort     Message = "password_too_short"
+	PasswordTooLong      Message = "password_too_long"
+	RoleInvalid          Message = "role_invalid"
+	FeatureOrTagRequired Message = "feature_or_tag_required"
+	ActiveUntilInvalid   Message = "active_until_invalid"
+	TimestampInvalid     Message = "timestamp_invalid"
+	RotationModeInvalid  Message = "rotation_mode_invalid"
+	RotationInUse        Message = "rotation_in_use"
+	WeightInvalid        Message = "weight_invalid"
+	ExperimentNotFound   Message = "experiment_not_found"
+	ExperimentExists     Message = "experiment_exists"
+	ExperimentStatus     Message = "experiment_status"
+	VariantsInvalid      Message = "variants_invalid"
+	VariantBannerInvalid Message = "variant_banner_invalid"
+	WinnerInvalid        Message = "winner_invalid"
+	GranularityInvalid   Message = "granularity_invalid"
+	StatsRangeInvalid    Message = "stats_range_invalid"
+	RotationRequired     Message = "rotation_required"
+	AuditRangeInvalid    Message = "audit_range_invalid"
+)
+
+var catalog = map[string]map[Message]string{
+	Russian: {
+		InternalError:   "Внутренняя ошибка сервера",
+		InvalidRequest:  "Некорректные данные",
+		Unauthenticated: "Пользователь не авторизован",
+		AccessDenied:    "Пользователь не имеет доступа",
+
+		InvalidCredentials: "Неверный логин или пароль",
+		TokensDisabled:     "Выпуск токенов отключен",
+		WarmupRunning:      "Прогрев кэша уже выполняется",
+		TooManyRequests:    "Слишком много запросов, попробуйте позже",
+
+		BannerNotFound:        "Баннер не найден",
+		BannerVersionNotFound: "Версия баннера не найдена",
+		FeatureNotFound:       "Фича не найдена",
+		TagNotFound:           "Тег не найден",
+		JobNotFound:           "Задача не найдена",
+		UserNotFound:          "Пользователь не найден",
+
+		BannerCombinationExists: "Баннер с такой фичей и тегом уже существует",
+		UserExists:              "Пользователь уже существует",
+
+		FieldRequired:        "обязательное поле",
+		FieldPositiveInt:     "должно быть положительным целым числом",
+		InvalidBody:          "некорректное тело запроса",
+		FeatureIDInvalid:     "неверный feature_id",
+		FeatureMissing:       "фича не существует",
+		TagIDsRequired:       "должен быть указан хотя бы один tag_id",
+		TagIDsInvalid:        "неверный или повторяющийся tag_id",
+		TagMissing:           "тег не существует",
+		ContentInvalid:       "неверное содержимое баннера",
+		VersionInvalid:       "неверная версия баннера",
+		CachePolicyInvalid:   "неверная политика кэширования",
+		NameInvalid:          "название должно быть непустым и не длиннее 255 символов",
+		LoginInvalid:         "неверный логин",
+		PasswordTooShort:     "слишком короткий пароль",
+		PasswordTooLong:      "слишком длинный пароль",
+		RoleInvalid:          "неверная роль",
+		FeatureOrTagRequired: "должен быть указан feature_id или tag_id",
+		ActiveUntilInvalid:   "active_until должен быть позже active_from",
+		TimestampInvalid:     "должно быть временем в формате RFC 3339",
+		RotationModeInvalid:  "неверный режим ротации",
+		RotationInUse:        "у фичи есть несколько баннеров с одним тегом",
+		WeightInvalid:        "вес должен быть положительным числом",
+		ExperimentNotFound:   "эксперимент не найден",
+		ExperimentExists:     "для этой фичи и тега уже есть незавершённый эксперимент",
+		ExperimentStatus:     "действие недоступно в текущем статусе эксперимента",
+		VariantsInvalid:      "нужно не менее двух вариантов с разными баннерами и долями трафика, в сумме дающими 100",
+		VariantBannerInvalid: "баннеры вариантов должны относиться к фиче и тегу эксперимента",
+		WinnerInvalid:        "победитель должен быть одним из вариантов",
+		GranularityInvalid:   "гранулярность должна быть hour или day",
+		StatsRangeInvalid:    "начало периода должно быть раньше конца",
+		RotationRequired:     "для эксперимента у фичи должен быть включён режим ротации weighted или round_robin",
+		AuditRangeInvalid:    "параметр from журнала аудита должен быть раньше to",
+	},
+	English: {
+		InternalError:   "Internal server error",
+		InvalidRequest:  "Invalid request",
+		Unauthenticated: "User is not authenticated",
+		AccessDenied:    "User does not have access",
+
+		InvalidCredentials: "Invalid login or password",
+		TokensDisabled:     "Token issuing is disabled",
+		WarmupRunning:      "Cache warm-up is already running",
+		TooManyRequests:    "Too many requests, try again later",
+
+		BannerNotFound:        "Banner not found",
+		BannerVersionNotFound: "Banner version not found",
+		FeatureNotFound:       "Feature not found",
+		TagNotFound:           "Tag not found",
+		JobNotFound:           "Job not found",
+		UserNotFound:          "User not found",
+
+		BannerCombinationExists: "A banner with this feature and tag already exists",
+		UserExists:              "User already exists",
+
+		FieldRequired:        "field is required",
+		FieldPositiveInt:     "must be a positive integer",
+		InvalidBody:          "malformed request body",
+		FeatureIDInvalid:     "invalid feature_id",
+		FeatureMissing:       "feature does not exist",
+		TagIDsRequired:       "at least one tag_id is required",
+		TagIDsInvalid:        "invalid or duplicate tag_id",
+		TagMissing:           "tag does not exist",
+		ContentInvalid:       "invalid banner content",
+		VersionInvalid:       "invalid banner version",
+		CachePolicyInvalid:   "invalid cache policy",
+		NameInvalid:          "name must be non-empty and at most 255 characters long",
+		LoginInvalid:         "invalid login",
+		PasswordTooShort:     "password is too short",
+		PasswordTooLong:      "password is too long",
+		RoleInvalid:          "invalid role",
+		FeatureOrTagRequired: "feature_id or tag_id is required",
+		ActiveUntilInvalid:   "active_until must be later than active_from",
+		TimestampInvalid:     "must be an RFC 3339 timestamp",
+		RotationModeInvalid:  "invalid rotation mode",
+		RotationInUse:        "the feature has several banners with the same tag",
+		WeightInvalid:        "weight must be a positive integer",
+		ExperimentNotFound:   "experiment not found",
+		ExperimentExists:     "the feature and tag already have an open experiment",
+		ExperimentStatus:     "the action is not allowed in the current experiment status",
+		VariantsInvalid:      "at least two variants with distinct banners and traffic adding up to 100 are required",
+		VariantBannerInvalid: "variant banners must belong to the experiment feature and tag",
+		WinnerInvalid:        "the winner must be one of the variants",
+		GranularityInvalid:   "granularity must be hour or day",
+		StatsRangeInvalid:    "the start of the period must be before its end",
+		RotationRequired:     "experiments require the feature rotation mode to be weighted or round_robin",
+		AuditRangeInvalid:    "audit log from must be before to",
+	},
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"banner-service/internal/config"
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type requestKey struct{}
+
+type requestState struct {
+	id     string
+	userID atomic.Value
+}
+
+func New(cfg config.LogConfig, w io.Writer) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		level = slog.LevelInfo
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	if cfg.Format == "text" {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestKey{}, &requestState{id: requestID})
+}
+
+func RequestID(ctx context.Context) string {
+	if state, ok := ctx.Value(requestKey{}).(*requestState); ok {
+		return state.id
+	}
+
+	return ""
+}
+
+func SetUserID(ctx context.Context, userID string) {
+	if state, ok := ctx.Value(requestKey{}).(*requestState); ok {
+		state.userID.Store(userID)
+	}
+}
+
+func UserID(ctx context.Context) string {
+	if state, ok := ctx.Value(requestKey{}).(*requestState); ok {
+		userID, _ := state.userID.Load().(string)
+		return userID
+	}
+
+	return ""
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if state, ok := ctx.Value(requestKey{}).(*requestState); ok {
+		record.AddAttrs(slog.String("request_id", state.id))
+		if userID, _ := state.userID.Load().(string); userID != "" {
+			record.AddAttrs(slog.String("user_id", userID))
+		}
+	}
+
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		record.AddAttrs(slog.String("trace_id", spanCtx.TraceID().String()), slog.String("span_id", spanCtx.SpanID().String()))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
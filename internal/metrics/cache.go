@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"banner-service/internal/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var cacheRequestsDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "cache", "requests_total"),
+	"Number of banner cache lookups by tier and result.",
+	[]string{"tier", "result"}, nil,
+)
+
+type CacheStatsProvider interface {
+	Stats() map[string]models.CacheStats
+}
+
+type cacheCollector struct {
+	cache CacheStatsProvider
+}
+
+func RegisterCacheCollector(cache CacheStatsProvider) {
+	prometheus.MustRegister(&cacheCollector{cache: cache})
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheRequestsDesc
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	for tier, stats := range c.cache.Stats() {
+		ch <- prometheus.MustNewConstMetric(cacheRequestsDesc, prometheus.CounterValue, float64(stats.Hits), tier, "hit")
+		ch <- prometheus.MustNewConstMetric(cacheRequestsDesc, prometheus.CounterValue, float64(stats.Misses), tier, "miss")
+		ch <- prometheus.MustNewConstMetric(cacheRequestsDesc, prometheus.CounterValue, float64(stats.Errors), tier, "error")
+	}
+}
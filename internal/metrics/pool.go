@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	poolAcquiredConnsDesc = poolDesc("acquired_connections", "Number of connections currently acquired from the pool.")
+	poolIdleConnsDesc     = poolDesc("idle_connections", "Number of idle connections in the pool.")
+	poolTotalConnsDesc    = poolDesc("total_connections", "Total number of connections in the pool.")
+	poolMaxConnsDesc      = poolDesc("max_connections", "Maximum size of the pool.")
+	poolAcquiresDesc      = poolDesc("acquires_total", "Number of successful connection acquires.")
+	poolEmptyAcquiresDesc = poolDesc("empty_acquires_total", "Number of acquires that had to wait for a connection.")
+	poolCanceledDesc      = poolDesc("canceled_acquires_total", "Number of acquires canceled by their context.")
+	poolAcquireWaitDesc   = poolDesc("acquire_duration_seconds_total", "Total time spent acquiring connections.")
+)
+
+type poolCollector struct {
+	pool *pgxpool.Pool
+}
+
+func RegisterPoolCollector(pool *pgxpool.Pool) {
+	prometheus.MustRegister(&poolCollector{pool: pool})
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredConnsDesc
+	ch <- poolIdleConnsDesc
+	ch <- poolTotalConnsDesc
+	ch <- poolMaxConnsDesc
+	ch <- poolAcquiresDesc
+	ch <- poolEmptyAcquiresDesc
+	ch <- poolCanceledDesc
+	ch <- poolAcquireWaitDesc
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(poolAcquiredConnsDesc, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdleConnsDesc, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotalConnsDesc, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMaxConnsDesc, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquiresDesc, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquiresDesc, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolCanceledDesc, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireWaitDesc, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
+
+func poolDesc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+}
@@ -1,44 +1,70 @@
 package middlewares
 
 import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/auth"
+	"banner-service/internal/i18n"
+	"banner-service/internal/logging"
+	"banner-service/internal/models"
+	bannerservice "banner-service/internal/services"
 	"context"
-	"fmt"
+	"errors"
 	"net/http"
 	"strings"
 
-	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
 )
 
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		tokenString := extractToken(r)
+var errNotAuthenticated = apperrors.Unauthorized(i18n.Unauthenticated)
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte("secret"), nil
-		})
+type TokenAuthenticator interface {
+	AuthenticateToken(ctx context.Context, token string) (*models.User, error)
+	GetUser(ctx context.Context, userID int) (*models.User, error)
+}
+
+func NewAuthMiddleware(verifier *auth.Verifier, users TokenAuthenticator) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenString := extractToken(r)
+
+			var principal *auth.Principal
+			if isJWT(tokenString) {
+				claims, err := verifier.Verify(r.Context(), tokenString)
+				if err != nil {
+					apperrors.Write(w, r, errNotAuthenticated)
+					return
+				}
+
+				principal = auth.PrincipalFromClaims(claims)
+				if userID, ok := claims["user_id"].(float64); ok && principal.Role != auth.RoleUser && !isReadOnly(r.Method) {
+					user, err := users.GetUser(r.Context(), int(userID))
+					if errors.Is(err, apperrors.ErrNotFound) {
+						apperrors.Write(w, r, errNotAuthenticated)
+						return
+					}
+					if err != nil {
+						apperrors.Write(w, r, err)
+						return
+					}
 
-		if err != nil {
-			http.Error(w, "Пользователь не авторизован", http.StatusUnauthorized)
-			return
-		}
+					principal = bannerservice.PrincipalForUser(user)
+				}
+			} else {
+				user, err := users.AuthenticateToken(r.Context(), tokenString)
+				if err != nil {
+					apperrors.Write(w, r, errNotAuthenticated)
+					return
+				}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			isAdmin := false
-			if adminFlag, ok := claims["admin"].(bool); ok {
-				isAdmin = adminFlag
+				principal = bannerservice.PrincipalForUser(user)
 			}
 
-			ctx := context.WithValue(r.Context(), "isAdminKey", isAdmin)
-			r = r.WithContext(ctx)
+			logging.SetUserID(r.Context(), principal.UserID)
+			r = r.WithContext(auth.NewContext(r.Context(), principal))
 
 			next.ServeHTTP(w, r)
-		} else {
-			http.Error(w, "Пользователь не авторизован", http.StatusUnauthorized)
-		}
-	})
+		})
+	}
 }
 
 func extractToken(r *http.Request) string {
@@ -49,3 +75,11 @@ func extractToken(r *http.Request) string {
 	}
 	return ""
 }
+
+func isReadOnly(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+func isJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
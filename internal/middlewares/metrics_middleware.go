@@ -0,0 +1,17 @@
+package middlewares
+
+import (
+	"banner-service/internal/metrics"
+	"net/http"
+	"time"
+)
+
+func MetricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(rec, r)
+
+		metrics.ObserveHTTPRequest(routeTemplate(r), r.Method, rec.status, time.Since(start))
+	})
+}
@@ -0,0 +1,63 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestRouteTemplate(t *testing.T) {
+	var got string
+	record := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+			got = routeTemplate(r)
+		})
+	}
+
+	r := mux.NewRouter()
+	r.Use(record)
+	r.HandleFunc("/banner/{id}", func(w http.ResponseWriter, r *http.Request) {}).Methods("GET")
+	r.NotFoundHandler = record(http.NotFoundHandler())
+	r.MethodNotAllowedHandler = record(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   string
+	}{
+		{"matched route", "GET", "/banner/42", "/banner/{id}"},
+		{"unknown path", "GET", "/missing/42", "unmatched"},
+		{"wrong method", "POST", "/banner/42", "unmatched"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = ""
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+			if got != tt.want {
+				t.Errorf("route = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusRecorderForwardsFlush(t *testing.T) {
+	w := httptest.NewRecorder()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+	if err := http.NewResponseController(rec).Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if !w.Flushed {
+		t.Error("flush was not forwarded to the underlying writer")
+	}
+	if _, _, err := http.NewResponseController(rec).Hijack(); err == nil {
+		t.Error("Hijack succeeded on a writer that does not support it")
+	}
+}
@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/auth"
+	"banner-service/internal/i18n"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+var errAccessDenied = apperrors.Forbidden(i18n.AccessDenied)
+
+func RequirePermission(perm auth.Permission) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.PrincipalFromContext(r.Context())
+			if !ok {
+				apperrors.Write(w, r, errNotAuthenticated)
+				return
+			}
+
+			if !principal.Can(perm) {
+				apperrors.Write(w, r, errAccessDenied)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditActionBannerCreate   = "banner.create"
+	AuditActionBannerUpdate   = "banner.update"
+	AuditActionBannerDelete   = "banner.delete"
+	AuditActionBannerActivate = "banner.activate_version"
+)
+
+type AuditEntry struct {
+	AuditID   int64           `json:"audit_id"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	BannerID  int             `json:"banner_id"`
+	Changes   json.RawMessage `json:"changes"`
+	RequestID string          `json:"request_id,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type AuditChange struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+type AuditFilter struct {
+	BannerID int
+	Actor    string
+	From     time.Time
+	To       time.Time
+	Limit    int
+	Offset   int
+}
@@ -5,12 +5,66 @@ import (
 	"time"
 )
 
+const DefaultBannerWeight = 1
+
 type Banner struct {
-	BannerID  int             `json:"banner_id"`
-	TagIDs    []int           `json:"tag_ids"`
-	FeatureID int             `json:"feature_id"`
-	Content   json.RawMessage `json:"content"`
-	IsActive  bool            `json:"is_active"`
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
+	BannerID    int             `json:"banner_id"`
+	TagIDs      []int           `json:"tag_ids"`
+	FeatureID   int             `json:"feature_id"`
+	Content     json.RawMessage `json:"content"`
+	IsActive    bool            `json:"is_active"`
+	ActiveFrom  *time.Time      `json:"active_from,omitempty"`
+	ActiveUntil *time.Time      `json:"active_until,omitempty"`
+	Weight      int             `json:"weight"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+func (b *Banner) IsLive(at time.Time) bool {
+	if !b.IsActive {
+		return false
+	}
+	if b.ActiveFrom != nil && at.Before(*b.ActiveFrom) {
+		return false
+	}
+
+	return b.ActiveUntil == nil || at.Before(*b.ActiveUntil)
+}
+
+type BannerCacheEntry struct {
+	Banners      []*Banner   `json:"banners,omitempty"`
+	RotationMode string      `json:"rotation_mode,omitempty"`
+	Experiment   *Experiment `json:"experiment,omitempty"`
+	NotFound     bool        `json:"not_found,omitempty"`
+	ExpiresAt    time.Time   `json:"expires_at,omitempty"`
+	CachedUntil  time.Time   `json:"cached_until,omitempty"`
+}
+
+type BannerVersion struct {
+	BannerID    int             `json:"banner_id"`
+	Version     int             `json:"version"`
+	TagIDs      []int           `json:"tag_ids"`
+	FeatureID   int             `json:"feature_id"`
+	Content     json.RawMessage `json:"content"`
+	IsActive    bool            `json:"is_active"`
+	ActiveFrom  *time.Time      `json:"active_from,omitempty"`
+	ActiveUntil *time.Time      `json:"active_until,omitempty"`
+	Weight      int             `json:"weight"`
+	Author      string          `json:"author"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type BannerFilter struct {
+	FeatureID  int
+	TagID      int
+	FeatureIDs []int
+	ActiveAt   *time.Time
+	Limit      int
+	Offset     int
+}
+
+type CacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	Errors uint64 `json:"errors"`
 }
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const (
+	ExperimentStatusRunning   = "running"
+	ExperimentStatusPaused    = "paused"
+	ExperimentStatusConcluded = "concluded"
+)
+
+type Experiment struct {
+	ExperimentID   int                 `json:"experiment_id"`
+	Name           string              `json:"name"`
+	FeatureID      int                 `json:"feature_id"`
+	TagID          int                 `json:"tag_id"`
+	Status         string              `json:"status"`
+	Variants       []ExperimentVariant `json:"variants"`
+	WinnerBannerID int                 `json:"winner_banner_id,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+type ExperimentVariant struct {
+	BannerID int `json:"banner_id"`
+	Traffic  int `json:"traffic"`
+}
+
+type ExperimentAssignment struct {
+	ExperimentID int
+	BannerID     int
+}
@@ -0,0 +1,28 @@
+package models
+
+const (
+	CachePolicyDefault = "default"
+	CachePolicyTTL     = "ttl"
+	CachePolicyNever   = "never"
+	CachePolicyForever = "forever"
+)
+
+const (
+	RotationModeNone       = "none"
+	RotationModeWeighted   = "weighted"
+	RotationModeRoundRobin = "round_robin"
+)
+
+type Feature struct {
+	FeatureID       int    `json:"feature_id"`
+	Name            string `json:"name"`
+	CachePolicy     string `json:"cache_policy"`
+	CacheTTLSeconds int    `json:"cache_ttl_seconds,omitempty"`
+	RotationMode    string `json:"rotation_mode"`
+}
+
+type DeletePreview struct {
+	Banners         int  `json:"banners"`
+	BannerTags      int  `json:"banner_tags"`
+	UntaggedBanners *int `json:"untagged_banners,omitempty"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+const (
+	JobKindDeleteBanners = "delete_banners"
+
+	JobStatusPending = "pending"
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	JobStatusFailed  = "failed"
+)
+
+type Job struct {
+	JobID     int       `json:"job_id"`
+	Kind      string    `json:"kind"`
+	FeatureID int       `json:"feature_id,omitempty"`
+	TagID     int       `json:"tag_id,omitempty"`
+	Status    string    `json:"status"`
+	Total     int       `json:"total"`
+	Processed int       `json:"processed"`
+	Error     string    `json:"error,omitempty"`
+	Author    string    `json:"author,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const (
+	StatsGranularityHour = "hour"
+	StatsGranularityDay  = "day"
+)
+
+type BannerStatsDelta struct {
+	BannerID    int
+	Bucket      time.Time
+	Impressions int64
+	Clicks      int64
+}
+
+type BannerStatsPoint struct {
+	Bucket      time.Time `json:"bucket"`
+	Impressions int64     `json:"impressions"`
+	Clicks      int64     `json:"clicks"`
+}
+
+type BannerStats struct {
+	BannerID    int                `json:"banner_id"`
+	Granularity string             `json:"granularity"`
+	From        time.Time          `json:"from"`
+	To          time.Time          `json:"to"`
+	Impressions int64              `json:"impressions"`
+	Clicks      int64              `json:"clicks"`
+	Points      []BannerStatsPoint `json:"points"`
+}
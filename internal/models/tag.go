@@ -0,0 +1,6 @@
+package models
+
+type Tag struct {
+	TagID int    `json:"tag_id"`
+	Name  string `json:"name"`
+}
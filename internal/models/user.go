@@ -0,0 +1,10 @@
+package models
+
+type User struct {
+	UserID       int    `json:"user_id"`
+	Login        string `json:"login"`
+	PasswordHash string `json:"-"`
+	IsAdmin      bool   `json:"is_admin"`
+	Role         string `json:"role"`
+	FeatureIDs   []int  `json:"feature_ids"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type WarmupStatus struct {
+	Running    bool       `json:"running"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Banners    int        `json:"banners"`
+	Keys       int        `json:"keys"`
+	Error      string     `json:"error,omitempty"`
+}
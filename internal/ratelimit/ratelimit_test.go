@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		keys  []string
+		want  []bool
+	}{
+		{"under the limit", 3, []string{"a", "a", "a"}, []bool{true, true, true}},
+		{"over the limit", 2, []string{"a", "a", "a", "a"}, []bool{true, true, false, false}},
+		{"keys are independent", 1, []string{"a", "b", "a", "b"}, []bool{true, true, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(tt.limit, time.Minute)
+			for i, key := range tt.keys {
+				if got := l.Allow(key); got != tt.want[i] {
+					t.Errorf("Allow(%s) #%d = %v, want %v", key, i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLimiterResetsAfterPeriod(t *testing.T) {
+	l := New(1, time.Minute)
+	if !l.Allow("a") || l.Allow("a") {
+		t.Fatal("limiter did not stop the second call in a window")
+	}
+
+	l.mu.Lock()
+	l.windows["a"].start = time.Now().Add(-time.Minute)
+	l.prunedAt = time.Now().Add(-time.Minute)
+	l.mu.Unlock()
+
+	if !l.Allow("a") {
+		t.Error("limiter still refuses calls after the period")
+	}
+	if n := len(l.windows); n != 1 {
+		t.Errorf("limiter keeps %d windows, want expired ones pruned", n)
+	}
+}
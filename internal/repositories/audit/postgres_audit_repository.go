@@ -0,0 +1,81 @@
+package auditrepo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"banner-service/internal/metrics"
+	"banner-service/internal/models"
+	txrepo "banner-service/internal/repositories/tx"
+)
+
+type PostgresAuditRepository struct {
+	pool txrepo.Pool
+}
+
+func NewPostgresAuditRepository(pool txrepo.Pool) *PostgresAuditRepository {
+	return &PostgresAuditRepository{
+		pool: pool,
+	}
+}
+
+func (r *PostgresAuditRepository) GetAuditEntries(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, error) {
+	defer metrics.ObserveQuery("audit", "GetAuditEntries")()
+
+	var queryParams []interface{}
+	whereConditions := []string{"1=1"}
+	if filter.BannerID > 0 {
+		whereConditions = append(whereConditions, fmt.Sprintf("banner_id = $%d", len(queryParams)+1))
+		queryParams = append(queryParams, filter.BannerID)
+	}
+	if filter.Actor != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("actor = $%d", len(queryParams)+1))
+		queryParams = append(queryParams, filter.Actor)
+	}
+	if !filter.From.IsZero() {
+		whereConditions = append(whereConditions, fmt.Sprintf("created_at >= $%d", len(queryParams)+1))
+		queryParams = append(queryParams, filter.From)
+	}
+	if !filter.To.IsZero() {
+		whereConditions = append(whereConditions, fmt.Sprintf("created_at < $%d", len(queryParams)+1))
+		queryParams = append(queryParams, filter.To)
+	}
+
+	query := fmt.Sprintf(`
+	SELECT audit_id, actor, action, banner_id, changes, request_id, created_at
+	FROM audit_log
+	WHERE %s
+	ORDER BY audit_id DESC
+	LIMIT $%d OFFSET $%d
+	`, strings.Join(whereConditions, " AND "), len(queryParams)+1, len(queryParams)+2)
+	queryParams = append(queryParams, filter.Limit, filter.Offset)
+
+	rows, err := r.pool.Query(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]*models.AuditEntry, 0)
+	for rows.Next() {
+		entry := &models.AuditEntry{}
+		if err := rows.Scan(
+			&entry.AuditID,
+			&entry.Actor,
+			&entry.Action,
+			&entry.BannerID,
+			&entry.Changes,
+			&entry.RequestID,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,111 @@
+package bannerrepo
+
+import (
+	"banner-service/internal/models"
+	"container/list"
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+type lruItem struct {
+	key       string
+	entry     *models.BannerCacheEntry
+	expiresAt time.Time
+}
+
+type LRUBannerRepository struct {
+	mu       sync.Mutex
+	capacity int
+	maxTTL   time.Duration
+	jitter   float64
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+func NewLRUBannerRepository(capacity int, maxTTL time.Duration, jitter float64) *LRUBannerRepository {
+	return &LRUBannerRepository{
+		capacity: capacity,
+		maxTTL:   maxTTL,
+		jitter:   jitter,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element, capacity),
+	}
+}
+
+func (r *LRUBannerRepository) GetBanner(ctx context.Context, key string) (*models.BannerCacheEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	el, ok := r.items[key]
+	if !ok {
+		return nil, nil
+	}
+
+	item := el.Value.(*lruItem)
+	if time.Now().After(item.expiresAt) {
+		r.removeElement(el)
+		return nil, nil
+	}
+
+	r.ll.MoveToFront(el)
+
+	return item.entry, nil
+}
+
+func (r *LRUBannerRepository) SetBanner(ctx context.Context, key string, entry *models.BannerCacheEntry, ttl time.Duration) error {
+	if ttl <= 0 || ttl > r.maxTTL {
+		ttl = r.maxTTL
+	}
+	if r.jitter > 0 {
+		ttl -= time.Duration(rand.Float64() * r.jitter * float64(ttl))
+	}
+	expiresAt := time.Now().Add(ttl)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if el, ok := r.items[key]; ok {
+		item := el.Value.(*lruItem)
+		item.entry = entry
+		item.expiresAt = expiresAt
+		r.ll.MoveToFront(el)
+		return nil
+	}
+
+	r.items[key] = r.ll.PushFront(&lruItem{key: key, entry: entry, expiresAt: expiresAt})
+	for r.ll.Len() > r.capacity {
+		r.removeElement(r.ll.Back())
+	}
+
+	return nil
+}
+
+func (r *LRUBannerRepository) DeleteBanners(ctx context.Context, keys ...string) error {
+	r.Invalidate(keys)
+	return nil
+}
+
+func (r *LRUBannerRepository) Invalidate(keys []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := r.items[key]; ok {
+			r.removeElement(el)
+		}
+	}
+}
+
+func (r *LRUBannerRepository) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.ll.Len()
+}
+
+func (r *LRUBannerRepository) removeElement(el *list.Element) {
+	r.ll.Remove(el)
+	delete(r.items, el.Value.(*lruItem).key)
+}
@@ -5,112 +5,167 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
+	"banner-service/internal/apperrors"
+	"banner-service/internal/config"
+	"banner-service/internal/i18n"
+	"banner-service/internal/metrics"
 	"banner-service/internal/models"
+	txrepo "banner-service/internal/repositories/tx"
+	"banner-service/internal/utils"
 
-	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	errBannerNotFound        = apperrors.NotFound(i18n.BannerNotFound)
+	errBannerVersionNotFound = apperrors.NotFound(i18n.BannerVersionNotFound)
 )
 
 type PostgresBannerRepository struct {
-	pool *pgxpool.Pool
+	pool   txrepo.Pool
+	cfg    config.BannersConfig
+	logger *slog.Logger
 }
 
-func NewPostgresBannerRepository(pool *pgxpool.Pool) *PostgresBannerRepository {
+func NewPostgresBannerRepository(pool txrepo.Pool, cfg config.BannersConfig, logger *slog.Logger) *PostgresBannerRepository {
 	return &PostgresBannerRepository{
-		pool: pool,
+		pool:   pool,
+		cfg:    cfg,
+		logger: logger,
 	}
 }
 
-func (r *PostgresBannerRepository) GetBanner(ctx context.Context, featureID, tagID int, isAdmin bool) (*models.Banner, error) {
-	whereConditions := "WHERE b.feature_id = $1 AND bt.tag_id = $2"
-	if !isAdmin {
-		whereConditions += " AND b.is_active = TRUE"
+func (r *PostgresBannerRepository) GetBannerCandidates(ctx context.Context, featureID, tagID int) (*models.Feature, []*models.Banner, error) {
+	defer metrics.ObserveQuery("banner", "GetBannerCandidates")()
+
+	query := `
+	SELECT f.feature_id, f.name, f.cache_policy, COALESCE(f.cache_ttl_seconds, 0), f.rotation_mode,
+		c.banner_id, c.content, c.is_active, c.active_from, c.active_until, c.weight, c.created_at, c.updated_at, c.tag_ids
+	FROM features f
+	LEFT JOIN LATERAL (
+		SELECT b.banner_id, b.content, b.is_active, b.active_from, b.active_until, b.weight, b.created_at, b.updated_at,
+			COALESCE(array_agg(all_bt.tag_id ORDER BY all_bt.tag_id), '{}') AS tag_ids
+		FROM banners b
+		INNER JOIN banner_tag bt ON b.banner_id = bt.banner_id AND bt.tag_id = $2
+		INNER JOIN banner_tag all_bt ON b.banner_id = all_bt.banner_id
+		WHERE b.feature_id = f.feature_id
+		GROUP BY b.banner_id
+	) c ON TRUE
+	WHERE f.feature_id = $1
+	ORDER BY c.banner_id
+	`
+
+	rows, err := r.pool.Query(ctx, query, featureID, tagID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var feature *models.Feature
+	banners := make([]*models.Banner, 0)
+	for rows.Next() {
+		var (
+			bannerID             *int
+			isActive             *bool
+			weight               *int
+			createdAt, updatedAt *time.Time
+		)
+		banner := &models.Banner{FeatureID: featureID}
+		feature = &models.Feature{}
+		if err := rows.Scan(
+			&feature.FeatureID,
+			&feature.Name,
+			&feature.CachePolicy,
+			&feature.CacheTTLSeconds,
+			&feature.RotationMode,
+			&bannerID,
+			&banner.Content,
+			&isActive,
+			&banner.ActiveFrom,
+			&banner.ActiveUntil,
+			&weight,
+			&createdAt,
+			&updatedAt,
+			&banner.TagIDs,
+		); err != nil {
+			return nil, nil, err
+		}
+		if bannerID == nil {
+			continue
+		}
+		banner.BannerID = *bannerID
+		banner.IsActive = *isActive
+		banner.Weight = *weight
+		banner.CreatedAt = *createdAt
+		banner.UpdatedAt = *updatedAt
+		banners = append(banners, banner)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(banners) == 0 {
+		return feature, nil, errBannerNotFound
 	}
 
-	query := fmt.Sprintf(`
-    SELECT b.banner_id, b.feature_id, b.content, b.is_active, b.created_at, b.updated_at
-    FROM banners b
-    INNER JOIN banner_tag bt ON b.banner_id = bt.banner_id
-    %s
-    GROUP BY b.banner_id, b.feature_id, b.content, b.is_active, b.created_at, b.updated_at
-    LIMIT 1
-    `, whereConditions)
+	return feature, banners, nil
+}
+
+func (r *PostgresBannerRepository) GetBannerByID(ctx context.Context, bannerID int) (*models.Banner, error) {
+	defer metrics.ObserveQuery("banner", "GetBannerByID")()
+
+	query := `
+	SELECT banner_id, feature_id, content, is_active, active_from, active_until, weight, created_at, updated_at
+	FROM banners
+	WHERE banner_id = $1
+	`
 
 	banner := &models.Banner{}
 
-	if err := r.pool.QueryRow(ctx, query, featureID, tagID).Scan(
+	if err := r.pool.QueryRow(ctx, query, bannerID).Scan(
 		&banner.BannerID,
 		&banner.FeatureID,
 		&banner.Content,
 		&banner.IsActive,
+		&banner.ActiveFrom,
+		&banner.ActiveUntil,
+		&banner.Weight,
 		&banner.CreatedAt,
 		&banner.UpdatedAt,
 	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errBannerNotFound
+		}
 		return nil, err
 	}
 
-	tagQuery := `
-	SELECT tag_id
-	FROM banner_tag
-	WHERE banner_id = $1
-	`
-	tagRows, err := r.pool.Query(ctx, tagQuery, banner.BannerID)
+	tagIDs, err := r.getBannerTagIDs(ctx, banner.BannerID)
 	if err != nil {
 		return nil, err
 	}
-	var tagIDs []int
-	for tagRows.Next() {
-		var tagID int
-		if err := tagRows.Scan(&tagID); err != nil {
-			return nil, err
-		}
-		tagIDs = append(tagIDs, tagID)
-	}
-	if err := tagRows.Err(); err != nil {
-		return nil, err
-	}
-	tagRows.Close()
 
 	banner.TagIDs = tagIDs
 
 	return banner, nil
 }
 
-func (r *PostgresBannerRepository) GetBanners(ctx context.Context, featureID, tagID, limit, offset int) ([]*models.Banner, error) {
-	var queryParams []interface{}
-	baseQuery := `
-	SELECT b.banner_id, b.feature_id, b.content, b.is_active, b.created_at, b.updated_at
-	FROM banners b
-	`
-
-	whereConditions := []string{"1=1"}
-	if featureID > 0 {
-		whereConditions = append(whereConditions, fmt.Sprintf("b.feature_id = $%d", len(queryParams)+1))
-		queryParams = append(queryParams, featureID)
-	}
-	if tagID > 0 {
-		baseQuery = `
-		SELECT b.banner_id, b.feature_id, b.content, b.is_active, b.created_at, b.updated_at
-		FROM banners b
-		LEFT JOIN banner_tag bt ON b.banner_id = bt.banner_id
-		`
-		whereConditions = append(whereConditions, fmt.Sprintf("bt.tag_id = $%d", len(queryParams)+1))
-		queryParams = append(queryParams, tagID)
-	}
-
-	if len(whereConditions) > 0 {
-		baseQuery += "WHERE " + strings.Join(whereConditions, " AND ")
-	}
+func (r *PostgresBannerRepository) GetBanners(ctx context.Context, filter models.BannerFilter) ([]*models.Banner, error) {
+	defer metrics.ObserveQuery("banner", "GetBanners")()
 
-	baseQuery += `
-	GROUP BY b.banner_id, b.feature_id, b.content, b.is_active, b.created_at, b.updated_at
+	from, queryParams := bannerFilter(filter)
+	baseQuery := `
+	SELECT b.banner_id, b.feature_id, b.content, b.is_active, b.active_from, b.active_until, b.weight, b.created_at, b.updated_at
+	` + from + `
+	GROUP BY b.banner_id, b.feature_id, b.content, b.is_active, b.active_from, b.active_until, b.weight, b.created_at, b.updated_at
 	`
 	var query string
-	if limit > 0 && offset >= 0 {
+	if filter.Limit > 0 && filter.Offset >= 0 {
 		query = fmt.Sprintf("%s ORDER BY b.updated_at DESC LIMIT $%d OFFSET $%d", baseQuery, len(queryParams)+1, len(queryParams)+2)
-		queryParams = append(queryParams, limit, offset)
+		queryParams = append(queryParams, filter.Limit, filter.Offset)
 	} else {
 		query = fmt.Sprintf("%s ORDER BY b.updated_at DESC", baseQuery)
 	}
@@ -129,42 +184,36 @@ func (r *PostgresBannerRepository) GetBanners(ctx context.Context, featureID, ta
 			&banner.FeatureID,
 			&banner.Content,
 			&banner.IsActive,
+			&banner.ActiveFrom,
+			&banner.ActiveUntil,
+			&banner.Weight,
 			&banner.CreatedAt,
 			&banner.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
 
-		tagQuery := `
-		SELECT tag_id
-		FROM banner_tag
-		WHERE banner_id = $1
-		`
-		tagRows, err := r.pool.Query(ctx, tagQuery, banner.BannerID)
+		banners = append(banners, banner)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for _, banner := range banners {
+		tagIDs, err := r.getBannerTagIDs(ctx, banner.BannerID)
 		if err != nil {
 			return nil, err
 		}
-		var tagIDs []int
-		for tagRows.Next() {
-			var tagID int
-			if err := tagRows.Scan(&tagID); err != nil {
-				return nil, err
-			}
-			tagIDs = append(tagIDs, tagID)
-		}
-		if err := tagRows.Err(); err != nil {
-			return nil, err
-		}
-		tagRows.Close()
-
 		banner.TagIDs = tagIDs
-		banners = append(banners, banner)
 	}
 
 	return banners, nil
 }
 
-func (r *PostgresBannerRepository) CreateBanner(ctx context.Context, banner *models.Banner) (int, error) {
+func (r *PostgresBannerRepository) CreateBanner(ctx context.Context, banner *models.Banner, author string) (int, error) {
+	defer metrics.ObserveQuery("banner", "CreateBanner")()
+
 	contentJSON, err := json.Marshal(banner.Content)
 	if err != nil {
 		return 0, err
@@ -177,21 +226,26 @@ func (r *PostgresBannerRepository) CreateBanner(ctx context.Context, banner *mod
 	defer tx.Rollback(ctx)
 
 	query := `
-	INSERT INTO banners (feature_id, content, is_active, created_at, updated_at)
-	VALUES ($1, $2, $3, $4, $5)
+	INSERT INTO banners (feature_id, content, is_active, active_from, active_until, weight, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	RETURNING banner_id
 	`
 
 	var bannerID int
-	if err := tx.QueryRow(ctx, query, banner.FeatureID, contentJSON, banner.IsActive, time.Now(), time.Now()).Scan(&bannerID); err != nil {
+	if err := tx.QueryRow(ctx, query, banner.FeatureID, contentJSON, banner.IsActive, banner.ActiveFrom, banner.ActiveUntil, banner.Weight, time.Now(), time.Now()).Scan(&bannerID); err != nil {
+		return 0, bannerWriteError(err)
+	}
+
+	if err := insertBannerTags(ctx, tx, bannerID, banner.TagIDs); err != nil {
 		return 0, err
 	}
 
-	for _, tagID := range banner.TagIDs {
-		_, err = tx.Exec(ctx, "INSERT INTO banner_tag (banner_id, tag_id) VALUES ($1, $2)", bannerID, tagID)
-		if err != nil {
-			return 0, err
-		}
+	if err := txrepo.RecordBannerChange(ctx, tx, author, models.AuditActionBannerCreate, bannerID, nil, banner); err != nil {
+		return 0, err
+	}
+
+	if err := r.saveBannerVersion(ctx, tx, bannerID, banner, contentJSON, author); err != nil {
+		return 0, err
 	}
 
 	if err := tx.Commit(ctx); err != nil {
@@ -201,7 +255,9 @@ func (r *PostgresBannerRepository) CreateBanner(ctx context.Context, banner *mod
 	return bannerID, nil
 }
 
-func (r *PostgresBannerRepository) UpdateBanner(ctx context.Context, bannerID int, banner *models.Banner) error {
+func (r *PostgresBannerRepository) UpdateBanner(ctx context.Context, bannerID int, banner *models.Banner, author string) error {
+	defer metrics.ObserveQuery("banner", "UpdateBanner")()
+
 	contentJSON, err := json.Marshal(banner.Content)
 	if err != nil {
 		return err
@@ -211,34 +267,41 @@ func (r *PostgresBannerRepository) UpdateBanner(ctx context.Context, bannerID in
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback(ctx)
 
-	defer func() {
-		if err != nil {
-			tx.Rollback(ctx)
-		}
-	}()
+	before, err := lockBanner(ctx, tx, bannerID)
+	if err != nil {
+		return err
+	}
 
 	query := `
 	UPDATE banners
-	SET feature_id = $1, content = $2, is_active = $3, updated_at = $4
-	WHERE banner_id = $5
+	SET feature_id = $1, content = $2, is_active = $3, active_from = $4, active_until = $5, weight = $6, updated_at = $7
+	WHERE banner_id = $8
 	`
 
-	if cmdTag, err := tx.Exec(ctx, query, banner.FeatureID, contentJSON, banner.IsActive, time.Now(), bannerID); err != nil {
-		return err
+	if cmdTag, err := tx.Exec(ctx, query, banner.FeatureID, contentJSON, banner.IsActive, banner.ActiveFrom, banner.ActiveUntil, banner.Weight, time.Now(), bannerID); err != nil {
+		return bannerWriteError(err)
 	} else if cmdTag.RowsAffected() != 1 {
-		return errors.New("no rows affected")
+		return errBannerNotFound
 	}
 
 	if _, err = tx.Exec(ctx, "DELETE FROM banner_tag WHERE banner_id = $1", bannerID); err != nil {
 		return err
 	}
 
-	for _, tagID := range banner.TagIDs {
-		if _, err = tx.Exec(ctx, "INSERT INTO banner_tag (banner_id, tag_id) VALUES ($1, $2)", bannerID, tagID); err != nil {
-			return err
-		}
+	if err = insertBannerTags(ctx, tx, bannerID, banner.TagIDs); err != nil {
+		return err
+	}
+
+	if err = txrepo.RecordBannerChange(ctx, tx, author, models.AuditActionBannerUpdate, bannerID, before, banner); err != nil {
+		return err
+	}
+
+	if err = r.saveBannerVersion(ctx, tx, bannerID, banner, contentJSON, author); err != nil {
+		return err
 	}
+
 	if err = tx.Commit(ctx); err != nil {
 		return err
 	}
@@ -246,18 +309,384 @@ func (r *PostgresBannerRepository) UpdateBanner(ctx context.Context, bannerID in
 	return nil
 }
 
-func (r *PostgresBannerRepository) DeleteBanner(ctx context.Context, bannerID int) error {
-	var ErrNoRowsAffected = errors.New("no rows affected")
+func (r *PostgresBannerRepository) DeleteBanner(ctx context.Context, bannerID int, author string) error {
+	defer metrics.ObserveQuery("banner", "DeleteBanner")()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := lockBanner(ctx, tx, bannerID)
+	if err != nil {
+		return err
+	}
+
 	query := `
 	DELETE FROM banners
 	WHERE banner_id = $1
 	`
 
-	if cmdTag, err := r.pool.Exec(ctx, query, bannerID); err != nil {
+	if cmdTag, err := tx.Exec(ctx, query, bannerID); err != nil {
 		return err
 	} else if cmdTag.RowsAffected() != 1 {
-		return ErrNoRowsAffected
+		return errBannerNotFound
+	}
+
+	if err := txrepo.RecordBannerChange(ctx, tx, author, models.AuditActionBannerDelete, bannerID, before, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresBannerRepository) GetBannerVersions(ctx context.Context, bannerID int) ([]*models.BannerVersion, error) {
+	defer metrics.ObserveQuery("banner", "GetBannerVersions")()
+
+	query := `
+	SELECT banner_id, version, feature_id, tag_ids, content, is_active, active_from, active_until, weight, author, created_at
+	FROM banner_versions
+	WHERE banner_id = $1
+	ORDER BY version DESC
+	LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, bannerID, r.cfg.VersionsLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make([]*models.BannerVersion, 0)
+	for rows.Next() {
+		version := &models.BannerVersion{}
+		if err := rows.Scan(
+			&version.BannerID,
+			&version.Version,
+			&version.FeatureID,
+			&version.TagIDs,
+			&version.Content,
+			&version.IsActive,
+			&version.ActiveFrom,
+			&version.ActiveUntil,
+			&version.Weight,
+			&version.Author,
+			&version.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		return nil, errBannerNotFound
+	}
+
+	return versions, nil
+}
+
+func (r *PostgresBannerRepository) ActivateBannerVersion(ctx context.Context, bannerID, version int, author string) error {
+	defer metrics.ObserveQuery("banner", "ActivateBannerVersion")()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := lockBanner(ctx, tx, bannerID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	SELECT feature_id, tag_ids, content, is_active, active_from, active_until, weight
+	FROM banner_versions
+	WHERE banner_id = $1 AND version = $2
+	`
+
+	banner := &models.Banner{BannerID: bannerID}
+	if err := tx.QueryRow(ctx, query, bannerID, version).Scan(
+		&banner.FeatureID,
+		&banner.TagIDs,
+		&banner.Content,
+		&banner.IsActive,
+		&banner.ActiveFrom,
+		&banner.ActiveUntil,
+		&banner.Weight,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errBannerVersionNotFound
+		}
+		return err
+	}
+
+	updateQuery := `
+	UPDATE banners
+	SET feature_id = $1, content = $2, is_active = $3, active_from = $4, active_until = $5, weight = $6, updated_at = $7
+	WHERE banner_id = $8
+	`
+
+	if cmdTag, err := tx.Exec(ctx, updateQuery, banner.FeatureID, []byte(banner.Content), banner.IsActive, banner.ActiveFrom, banner.ActiveUntil, banner.Weight, time.Now(), bannerID); err != nil {
+		return bannerWriteError(err)
+	} else if cmdTag.RowsAffected() != 1 {
+		return errBannerNotFound
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM banner_tag WHERE banner_id = $1", bannerID); err != nil {
+		return err
+	}
+
+	if err := insertBannerTags(ctx, tx, bannerID, banner.TagIDs); err != nil {
+		return err
+	}
+
+	if err := txrepo.RecordBannerChange(ctx, tx, author, models.AuditActionBannerActivate, bannerID, before, banner); err != nil {
+		return err
+	}
+
+	if err := r.saveBannerVersion(ctx, tx, bannerID, banner, banner.Content, author); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func lockBanner(ctx context.Context, tx pgx.Tx, bannerID int) (*models.Banner, error) {
+	banners, err := txrepo.LockBanners(ctx, tx, "b.banner_id = $1", bannerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(banners) == 0 {
+		return nil, errBannerNotFound
+	}
+
+	return banners[0], nil
+}
+
+func insertBannerTags(ctx context.Context, tx pgx.Tx, bannerID int, tagIDs []int) error {
+	for _, tagID := range tagIDs {
+		if _, err := tx.Exec(ctx, "INSERT INTO banner_tag (banner_id, tag_id) VALUES ($1, $2)", bannerID, tagID); err != nil {
+			return bannerWriteError(err)
+		}
 	}
 
 	return nil
 }
+
+func bannerWriteError(err error) error {
+	switch {
+	case utils.IsUniqueCombinationViolation(err):
+		return apperrors.Conflict(i18n.BannerCombinationExists, err)
+	case utils.IsUniqueViolation(err):
+		return apperrors.InvalidField("tag_ids", i18n.TagIDsInvalid)
+	case utils.IsForeignKeyViolation(err) && utils.ViolatedTable(err) == "banner_tag":
+		return apperrors.InvalidField("tag_ids", i18n.TagMissing)
+	case utils.IsForeignKeyViolation(err):
+		return apperrors.InvalidField("feature_id", i18n.FeatureMissing)
+	}
+
+	return err
+}
+
+func (r *PostgresBannerRepository) saveBannerVersion(ctx context.Context, tx pgx.Tx, bannerID int, banner *models.Banner, contentJSON []byte, author string) error {
+	var version int
+	if err := tx.QueryRow(ctx, "SELECT COALESCE(MAX(version), 0) + 1 FROM banner_versions WHERE banner_id = $1", bannerID).Scan(&version); err != nil {
+		return err
+	}
+
+	tagIDs := banner.TagIDs
+	if tagIDs == nil {
+		tagIDs = []int{}
+	}
+
+	query := `
+	INSERT INTO banner_versions (banner_id, version, feature_id, tag_ids, content, is_active, active_from, active_until, weight, author, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	if _, err := tx.Exec(ctx, query, bannerID, version, banner.FeatureID, tagIDs, contentJSON, banner.IsActive, banner.ActiveFrom, banner.ActiveUntil, banner.Weight, author, time.Now()); err != nil {
+		return err
+	}
+
+	cmdTag, err := tx.Exec(ctx, "DELETE FROM banner_versions WHERE banner_id = $1 AND version <= $2", bannerID, version-r.cfg.VersionsLimit)
+	if err != nil {
+		return err
+	}
+
+	r.logger.DebugContext(ctx, "saved banner version", "banner_id", bannerID, "version", version, "pruned", cmdTag.RowsAffected())
+
+	return nil
+}
+
+func (r *PostgresBannerRepository) CountBanners(ctx context.Context, filter models.BannerFilter) (int, error) {
+	defer metrics.ObserveQuery("banner", "CountBanners")()
+
+	from, queryParams := bannerFilter(filter)
+	query := "SELECT COUNT(DISTINCT b.banner_id) " + from
+
+	var count int
+	if err := r.pool.QueryRow(ctx, query, queryParams...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *PostgresBannerRepository) DeleteBannersBatch(ctx context.Context, filter models.BannerFilter, batchSize int, author string) ([]*models.Banner, error) {
+	defer metrics.ObserveQuery("banner", "DeleteBannersBatch")()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	from, queryParams := bannerFilter(filter)
+	condition := fmt.Sprintf(`b.banner_id IN (
+		SELECT DISTINCT b.banner_id
+		%s
+		LIMIT $%d
+	)`, from, len(queryParams)+1)
+	queryParams = append(queryParams, batchSize)
+
+	banners, err := txrepo.LockBanners(ctx, tx, condition, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(banners) == 0 {
+		return banners, nil
+	}
+
+	bannerIDs := make([]int, 0, len(banners))
+	for _, banner := range banners {
+		bannerIDs = append(bannerIDs, banner.BannerID)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM banners WHERE banner_id = ANY($1)", bannerIDs); err != nil {
+		return nil, err
+	}
+
+	for _, banner := range banners {
+		if err := txrepo.RecordBannerChange(ctx, tx, author, models.AuditActionBannerDelete, banner.BannerID, banner, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	r.logger.DebugContext(ctx, "deleted banner batch", "feature_id", filter.FeatureID, "tag_id", filter.TagID, "deleted", len(banners))
+
+	return banners, nil
+}
+
+func (r *PostgresBannerRepository) GetActiveBannersAfter(ctx context.Context, afterID, limit int) ([]*models.Banner, error) {
+	defer metrics.ObserveQuery("banner", "GetActiveBannersAfter")()
+
+	query := `
+	SELECT b.banner_id, b.feature_id, b.content, b.is_active, b.active_from, b.active_until, b.weight, b.created_at, b.updated_at,
+		COALESCE(array_agg(bt.tag_id ORDER BY bt.tag_id) FILTER (WHERE bt.tag_id IS NOT NULL), '{}')
+	FROM banners b
+	LEFT JOIN banner_tag bt ON b.banner_id = bt.banner_id
+	WHERE b.is_active = TRUE AND (b.active_until IS NULL OR b.active_until > now()) AND b.banner_id > $1
+	GROUP BY b.banner_id
+	ORDER BY b.banner_id
+	LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	banners := make([]*models.Banner, 0, limit)
+	for rows.Next() {
+		banner := &models.Banner{}
+		if err := rows.Scan(
+			&banner.BannerID,
+			&banner.FeatureID,
+			&banner.Content,
+			&banner.IsActive,
+			&banner.ActiveFrom,
+			&banner.ActiveUntil,
+			&banner.Weight,
+			&banner.CreatedAt,
+			&banner.UpdatedAt,
+			&banner.TagIDs,
+		); err != nil {
+			return nil, err
+		}
+		banners = append(banners, banner)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return banners, nil
+}
+
+func (r *PostgresBannerRepository) getBannerTagIDs(ctx context.Context, bannerID int) ([]int, error) {
+	tagQuery := `
+	SELECT tag_id
+	FROM banner_tag
+	WHERE banner_id = $1
+	`
+	tagRows, err := r.pool.Query(ctx, tagQuery, bannerID)
+	if err != nil {
+		return nil, err
+	}
+	defer tagRows.Close()
+
+	var tagIDs []int
+	for tagRows.Next() {
+		var tagID int
+		if err := tagRows.Scan(&tagID); err != nil {
+			return nil, err
+		}
+		tagIDs = append(tagIDs, tagID)
+	}
+	if err := tagRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tagIDs, nil
+}
+
+func bannerFilter(filter models.BannerFilter) (string, []interface{}) {
+	var queryParams []interface{}
+	from := `
+	FROM banners b
+	`
+
+	whereConditions := []string{"1=1"}
+	if filter.FeatureID > 0 {
+		whereConditions = append(whereConditions, fmt.Sprintf("b.feature_id = $%d", len(queryParams)+1))
+		queryParams = append(queryParams, filter.FeatureID)
+	}
+	if filter.FeatureIDs != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("b.feature_id = ANY($%d)", len(queryParams)+1))
+		queryParams = append(queryParams, filter.FeatureIDs)
+	}
+	if filter.ActiveAt != nil {
+		whereConditions = append(whereConditions, liveCondition(fmt.Sprintf("$%d", len(queryParams)+1)))
+		queryParams = append(queryParams, *filter.ActiveAt)
+	}
+	if filter.TagID > 0 {
+		from += `LEFT JOIN banner_tag bt ON b.banner_id = bt.banner_id
+	`
+		whereConditions = append(whereConditions, fmt.Sprintf("bt.tag_id = $%d", len(queryParams)+1))
+		queryParams = append(queryParams, filter.TagID)
+	}
+
+	return from + "WHERE " + strings.Join(whereConditions, " AND "), queryParams
+}
+
+func liveCondition(at string) string {
+	return fmt.Sprintf("b.is_active = TRUE AND (b.active_from IS NULL OR b.active_from <= %[1]s) AND (b.active_until IS NULL OR b.active_until > %[1]s)", at)
+}
@@ -4,20 +4,24 @@ import (
 	"banner-service/internal/models"
 	"context"
 	"encoding/json"
+	"log/slog"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 )
 
+const invalidationChannel = "banner-cache-invalidation"
+
 type RedisBannerRepository struct {
 	client *redis.Client
+	logger *slog.Logger
 }
 
-func NewRedisBannerRepository(client *redis.Client) *RedisBannerRepository {
-	return &RedisBannerRepository{client: client}
+func NewRedisBannerRepository(client *redis.Client, logger *slog.Logger) *RedisBannerRepository {
+	return &RedisBannerRepository{client: client, logger: logger}
 }
 
-func (r *RedisBannerRepository) GetBanner(ctx context.Context, key string) (*models.Banner, error) {
+func (r *RedisBannerRepository) GetBanner(ctx context.Context, key string) (*models.BannerCacheEntry, error) {
 	val, err := r.client.Get(ctx, key).Result()
 	if err == redis.Nil {
 		return nil, nil
@@ -25,20 +29,64 @@ func (r *RedisBannerRepository) GetBanner(ctx context.Context, key string) (*mod
 		return nil, err
 	}
 
-	var banner models.Banner
-	err = json.Unmarshal([]byte(val), &banner)
+	var entry models.BannerCacheEntry
+	err = json.Unmarshal([]byte(val), &entry)
 	if err != nil {
 		return nil, err
 	}
+	if len(entry.Banners) == 0 && !entry.NotFound {
+		return nil, nil
+	}
 
-	return &banner, nil
+	return &entry, nil
 }
 
-func (r *RedisBannerRepository) SetBanner(ctx context.Context, key string, banner *models.Banner, ttl time.Duration) error {
-	val, err := json.Marshal(banner)
+func (r *RedisBannerRepository) SetBanner(ctx context.Context, key string, entry *models.BannerCacheEntry, ttl time.Duration) error {
+	val, err := json.Marshal(entry)
 	if err != nil {
 		return err
 	}
 
 	return r.client.Set(ctx, key, val, ttl).Err()
 }
+
+func (r *RedisBannerRepository) DeleteBanners(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+		return err
+	}
+
+	msg, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Publish(ctx, invalidationChannel, msg).Err()
+}
+
+func (r *RedisBannerRepository) SubscribeInvalidations(ctx context.Context, onInvalidate func(keys []string)) {
+	pubsub := r.client.Subscribe(ctx, invalidationChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			var keys []string
+			if err := json.Unmarshal([]byte(msg.Payload), &keys); err != nil {
+				r.logger.WarnContext(ctx, "could not decode cache invalidation message", "payload", msg.Payload, "error", err)
+				continue
+			}
+			onInvalidate(keys)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package bannerrepo
+
+import (
+	"banner-service/internal/models"
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+type BannerCache interface {
+	GetBanner(ctx context.Context, key string) (*models.BannerCacheEntry, error)
+	SetBanner(ctx context.Context, key string, entry *models.BannerCacheEntry, ttl time.Duration) error
+	DeleteBanners(ctx context.Context, keys ...string) error
+}
+
+type CacheTier struct {
+	Name  string
+	Cache BannerCache
+}
+
+type tierCounters struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	errors atomic.Uint64
+}
+
+type TieredBannerRepository struct {
+	tiers       []CacheTier
+	counters    []*tierCounters
+	backfillTTL time.Duration
+	logger      *slog.Logger
+}
+
+func NewTieredBannerRepository(backfillTTL time.Duration, logger *slog.Logger, tiers ...CacheTier) *TieredBannerRepository {
+	counters := make([]*tierCounters, len(tiers))
+	for i := range counters {
+		counters[i] = &tierCounters{}
+	}
+
+	return &TieredBannerRepository{
+		tiers:       tiers,
+		counters:    counters,
+		backfillTTL: backfillTTL,
+		logger:      logger,
+	}
+}
+
+func (r *TieredBannerRepository) GetBanner(ctx context.Context, key string) (*models.BannerCacheEntry, error) {
+	for i, tier := range r.tiers {
+		entry, err := tier.Cache.GetBanner(ctx, key)
+		if err != nil {
+			r.counters[i].errors.Add(1)
+			r.logger.WarnContext(ctx, "could not read banner cache tier", "tier", tier.Name, "key", key, "error", err)
+			continue
+		}
+		if entry == nil {
+			r.counters[i].misses.Add(1)
+			continue
+		}
+
+		r.counters[i].hits.Add(1)
+		ttl := r.backfillTTL
+		if !entry.CachedUntil.IsZero() {
+			ttl = min(ttl, time.Until(entry.CachedUntil))
+		}
+		for j := 0; j < i && ttl > 0; j++ {
+			if err := r.tiers[j].Cache.SetBanner(ctx, key, entry, ttl); err != nil {
+				r.counters[j].errors.Add(1)
+				r.logger.WarnContext(ctx, "could not backfill banner cache tier", "tier", r.tiers[j].Name, "key", key, "error", err)
+			}
+		}
+
+		return entry, nil
+	}
+
+	return nil, nil
+}
+
+func (r *TieredBannerRepository) SetBanner(ctx context.Context, key string, entry *models.BannerCacheEntry, ttl time.Duration) error {
+	if ttl > 0 {
+		stored := *entry
+		stored.CachedUntil = time.Now().Add(ttl)
+		entry = &stored
+	}
+
+	var errs []error
+	for i, tier := range r.tiers {
+		if err := tier.Cache.SetBanner(ctx, key, entry, ttl); err != nil {
+			r.counters[i].errors.Add(1)
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *TieredBannerRepository) DeleteBanners(ctx context.Context, keys ...string) error {
+	var errs []error
+	for i := len(r.tiers) - 1; i >= 0; i-- {
+		if err := r.tiers[i].Cache.DeleteBanners(ctx, keys...); err != nil {
+			r.counters[i].errors.Add(1)
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *TieredBannerRepository) Stats() map[string]models.CacheStats {
+	stats := make(map[string]models.CacheStats, len(r.tiers))
+	for i, tier := range r.tiers {
+		stats[tier.Name] = models.CacheStats{
+			Hits:   r.counters[i].hits.Load(),
+			Misses: r.counters[i].misses.Load(),
+			Errors: r.counters[i].errors.Load(),
+		}
+	}
+
+	return stats
+}
@@ -0,0 +1,74 @@
+package bannerrepo
+
+import (
+	"banner-service/internal/models"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestTieredBannerRepositoryBackfillKeepsEntryTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		wantTTL time.Duration
+	}{
+		{"short-lived entry", 5 * time.Second, 5 * time.Second},
+		{"long-lived entry", time.Hour, 30 * time.Second},
+		{"entry without expiry", 0, 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			local := NewLRUBannerRepository(10, 30*time.Second, 0)
+			remote := NewLRUBannerRepository(10, 24*time.Hour, 0)
+			r := NewTieredBannerRepository(30*time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)),
+				CacheTier{Name: "local", Cache: local},
+				CacheTier{Name: "remote", Cache: remote},
+			)
+
+			if err := r.SetBanner(context.Background(), "key", &models.BannerCacheEntry{NotFound: true}, tt.ttl); err != nil {
+				t.Fatalf("SetBanner: %v", err)
+			}
+			local.Invalidate([]string{"key"})
+
+			if entry, err := r.GetBanner(context.Background(), "key"); err != nil || entry == nil {
+				t.Fatalf("GetBanner = %v, %v, want the remote entry", entry, err)
+			}
+			backfilledAt := time.Now()
+
+			local.mu.Lock()
+			el, ok := local.items["key"]
+			local.mu.Unlock()
+			if !ok {
+				t.Fatal("local tier was not backfilled")
+			}
+			if ttl := el.Value.(*lruItem).expiresAt.Sub(backfilledAt); ttl > tt.wantTTL {
+				t.Errorf("backfilled TTL = %v, want at most %v", ttl, tt.wantTTL)
+			}
+		})
+	}
+}
+
+func TestTieredBannerRepositorySkipsBackfillOfExpiredEntry(t *testing.T) {
+	local := NewLRUBannerRepository(10, 30*time.Second, 0)
+	remote := NewLRUBannerRepository(10, time.Hour, 0)
+	r := NewTieredBannerRepository(30*time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)),
+		CacheTier{Name: "local", Cache: local},
+		CacheTier{Name: "remote", Cache: remote},
+	)
+
+	entry := &models.BannerCacheEntry{NotFound: true, CachedUntil: time.Now().Add(-time.Second)}
+	if err := remote.SetBanner(context.Background(), "key", entry, time.Hour); err != nil {
+		t.Fatalf("SetBanner: %v", err)
+	}
+
+	if _, err := r.GetBanner(context.Background(), "key"); err != nil {
+		t.Fatalf("GetBanner: %v", err)
+	}
+	if n := local.Len(); n != 0 {
+		t.Errorf("local tier holds %d entries, want an expired entry not backfilled", n)
+	}
+}
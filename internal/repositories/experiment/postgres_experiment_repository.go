@@ -0,0 +1,247 @@
+package experimentrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"banner-service/internal/apperrors"
+	"banner-service/internal/i18n"
+	"banner-service/internal/metrics"
+	"banner-service/internal/models"
+	txrepo "banner-service/internal/repositories/tx"
+	"banner-service/internal/utils"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const experimentSelectColumns = `experiment_id, name, feature_id, tag_id, status, COALESCE(winner_banner_id, 0), created_at, updated_at`
+
+var errExperimentNotFound = apperrors.NotFound(i18n.ExperimentNotFound)
+
+type PostgresExperimentRepository struct {
+	pool txrepo.Pool
+}
+
+func NewPostgresExperimentRepository(pool txrepo.Pool) *PostgresExperimentRepository {
+	return &PostgresExperimentRepository{
+		pool: pool,
+	}
+}
+
+func (r *PostgresExperimentRepository) GetExperiments(ctx context.Context, featureIDs []int, limit, offset int) ([]*models.Experiment, error) {
+	defer metrics.ObserveQuery("experiment", "GetExperiments")()
+
+	var queryParams []interface{}
+	query := `
+	SELECT ` + experimentSelectColumns + `
+	FROM experiments
+	`
+	if featureIDs != nil {
+		query += fmt.Sprintf("WHERE feature_id = ANY($%d)\n", len(queryParams)+1)
+		queryParams = append(queryParams, featureIDs)
+	}
+	query += "ORDER BY experiment_id DESC\n"
+	if limit > 0 && offset >= 0 {
+		query += fmt.Sprintf("LIMIT $%d OFFSET $%d", len(queryParams)+1, len(queryParams)+2)
+		queryParams = append(queryParams, limit, offset)
+	}
+
+	rows, err := r.pool.Query(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	experiments := make([]*models.Experiment, 0)
+	for rows.Next() {
+		experiment, err := scanExperiment(rows)
+		if err != nil {
+			return nil, err
+		}
+		experiments = append(experiments, experiment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for _, experiment := range experiments {
+		if experiment.Variants, err = r.getVariants(ctx, experiment.ExperimentID); err != nil {
+			return nil, err
+		}
+	}
+
+	return experiments, nil
+}
+
+func (r *PostgresExperimentRepository) GetExperiment(ctx context.Context, experimentID int) (*models.Experiment, error) {
+	defer metrics.ObserveQuery("experiment", "GetExperiment")()
+
+	query := `SELECT ` + experimentSelectColumns + ` FROM experiments WHERE experiment_id = $1`
+
+	experiment, err := scanExperiment(r.pool.QueryRow(ctx, query, experimentID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errExperimentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if experiment.Variants, err = r.getVariants(ctx, experiment.ExperimentID); err != nil {
+		return nil, err
+	}
+
+	return experiment, nil
+}
+
+func (r *PostgresExperimentRepository) GetCurrentExperiment(ctx context.Context, featureID, tagID int) (*models.Experiment, error) {
+	defer metrics.ObserveQuery("experiment", "GetCurrentExperiment")()
+
+	query := `
+	SELECT ` + experimentSelectColumns + `
+	FROM experiments
+	WHERE feature_id = $1 AND tag_id = $2
+	ORDER BY status <> $3 DESC, updated_at DESC
+	LIMIT 1
+	`
+
+	experiment, err := scanExperiment(r.pool.QueryRow(ctx, query, featureID, tagID, models.ExperimentStatusConcluded))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if experiment.Variants, err = r.getVariants(ctx, experiment.ExperimentID); err != nil {
+		return nil, err
+	}
+
+	return experiment, nil
+}
+
+func (r *PostgresExperimentRepository) CreateExperiment(ctx context.Context, experiment *models.Experiment) (int, error) {
+	defer metrics.ObserveQuery("experiment", "CreateExperiment")()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	bannerIDs := make([]int, 0, len(experiment.Variants))
+	for _, variant := range experiment.Variants {
+		bannerIDs = append(bannerIDs, variant.BannerID)
+	}
+
+	checkQuery := `
+	SELECT COUNT(DISTINCT b.banner_id)
+	FROM banners b
+	INNER JOIN banner_tag bt ON b.banner_id = bt.banner_id
+	WHERE b.feature_id = $1 AND bt.tag_id = $2 AND b.banner_id = ANY($3)
+	`
+
+	var matched int
+	if err := tx.QueryRow(ctx, checkQuery, experiment.FeatureID, experiment.TagID, bannerIDs).Scan(&matched); err != nil {
+		return 0, err
+	}
+	if matched != len(bannerIDs) {
+		return 0, apperrors.InvalidField("variants", i18n.VariantBannerInvalid)
+	}
+
+	query := `
+	INSERT INTO experiments (name, feature_id, tag_id, status, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $5)
+	RETURNING experiment_id
+	`
+
+	var experimentID int
+	if err := tx.QueryRow(ctx, query, experiment.Name, experiment.FeatureID, experiment.TagID, models.ExperimentStatusRunning, time.Now()).Scan(&experimentID); err != nil {
+		if utils.IsUniqueViolation(err) {
+			return 0, apperrors.Conflict(i18n.ExperimentExists, err)
+		}
+		return 0, err
+	}
+
+	for _, variant := range experiment.Variants {
+		if _, err := tx.Exec(ctx, "INSERT INTO experiment_variants (experiment_id, banner_id, traffic) VALUES ($1, $2, $3)", experimentID, variant.BannerID, variant.Traffic); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return experimentID, nil
+}
+
+func (r *PostgresExperimentRepository) SetExperimentStatus(ctx context.Context, experimentID int, status string, winnerBannerID int) error {
+	defer metrics.ObserveQuery("experiment", "SetExperimentStatus")()
+
+	query := `
+	UPDATE experiments
+	SET status = $1, winner_banner_id = NULLIF($2, 0), updated_at = $3
+	WHERE experiment_id = $4
+	`
+
+	if cmdTag, err := r.pool.Exec(ctx, query, status, winnerBannerID, time.Now(), experimentID); err != nil {
+		if utils.IsUniqueViolation(err) {
+			return apperrors.Conflict(i18n.ExperimentExists, err)
+		}
+		return err
+	} else if cmdTag.RowsAffected() != 1 {
+		return errExperimentNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresExperimentRepository) getVariants(ctx context.Context, experimentID int) ([]models.ExperimentVariant, error) {
+	query := `
+	SELECT banner_id, traffic
+	FROM experiment_variants
+	WHERE experiment_id = $1
+	ORDER BY banner_id
+	`
+
+	rows, err := r.pool.Query(ctx, query, experimentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants := make([]models.ExperimentVariant, 0)
+	for rows.Next() {
+		var variant models.ExperimentVariant
+		if err := rows.Scan(&variant.BannerID, &variant.Traffic); err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+func scanExperiment(row pgx.Row) (*models.Experiment, error) {
+	experiment := &models.Experiment{}
+	if err := row.Scan(
+		&experiment.ExperimentID,
+		&experiment.Name,
+		&experiment.FeatureID,
+		&experiment.TagID,
+		&experiment.Status,
+		&experiment.WinnerBannerID,
+		&experiment.CreatedAt,
+		&experiment.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return experiment, nil
+}
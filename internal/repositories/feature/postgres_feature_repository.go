@@ -0,0 +1,215 @@
+package featurerepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"banner-service/internal/apperrors"
+	"banner-service/internal/i18n"
+	"banner-service/internal/metrics"
+	"banner-service/internal/models"
+	txrepo "banner-service/internal/repositories/tx"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const featureSelectColumns = `feature_id, name, cache_policy, COALESCE(cache_ttl_seconds, 0), rotation_mode`
+
+var errFeatureNotFound = apperrors.NotFound(i18n.FeatureNotFound)
+
+type PostgresFeatureRepository struct {
+	pool txrepo.Pool
+}
+
+func NewPostgresFeatureRepository(pool txrepo.Pool) *PostgresFeatureRepository {
+	return &PostgresFeatureRepository{
+		pool: pool,
+	}
+}
+
+func (r *PostgresFeatureRepository) GetFeatures(ctx context.Context, limit, offset int) ([]*models.Feature, error) {
+	defer metrics.ObserveQuery("feature", "GetFeatures")()
+
+	var queryParams []interface{}
+	query := `
+	SELECT ` + featureSelectColumns + `
+	FROM features
+	ORDER BY feature_id
+	`
+	if limit > 0 && offset >= 0 {
+		query += fmt.Sprintf("LIMIT $%d OFFSET $%d", len(queryParams)+1, len(queryParams)+2)
+		queryParams = append(queryParams, limit, offset)
+	}
+
+	rows, err := r.pool.Query(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	features := make([]*models.Feature, 0)
+	for rows.Next() {
+		feature := &models.Feature{}
+		if err := rows.Scan(&feature.FeatureID, &feature.Name, &feature.CachePolicy, &feature.CacheTTLSeconds, &feature.RotationMode); err != nil {
+			return nil, err
+		}
+		features = append(features, feature)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return features, nil
+}
+
+func (r *PostgresFeatureRepository) GetFeature(ctx context.Context, featureID int) (*models.Feature, error) {
+	defer metrics.ObserveQuery("feature", "GetFeature")()
+
+	feature := &models.Feature{}
+	if err := r.pool.QueryRow(ctx, "SELECT "+featureSelectColumns+" FROM features WHERE feature_id = $1", featureID).Scan(
+		&feature.FeatureID,
+		&feature.Name,
+		&feature.CachePolicy,
+		&feature.CacheTTLSeconds,
+		&feature.RotationMode,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errFeatureNotFound
+		}
+		return nil, err
+	}
+
+	return feature, nil
+}
+
+func (r *PostgresFeatureRepository) CreateFeature(ctx context.Context, feature *models.Feature) (int, error) {
+	defer metrics.ObserveQuery("feature", "CreateFeature")()
+
+	var featureID int
+	if err := r.pool.QueryRow(ctx, "INSERT INTO features (name) VALUES ($1) RETURNING feature_id", feature.Name).Scan(&featureID); err != nil {
+		return 0, err
+	}
+
+	return featureID, nil
+}
+
+func (r *PostgresFeatureRepository) UpdateFeature(ctx context.Context, featureID int, feature *models.Feature) error {
+	defer metrics.ObserveQuery("feature", "UpdateFeature")()
+
+	if cmdTag, err := r.pool.Exec(ctx, "UPDATE features SET name = $1 WHERE feature_id = $2", feature.Name, featureID); err != nil {
+		return err
+	} else if cmdTag.RowsAffected() != 1 {
+		return errFeatureNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresFeatureRepository) SetFeatureCachePolicy(ctx context.Context, featureID int, policy string, ttlSeconds int) error {
+	defer metrics.ObserveQuery("feature", "SetFeatureCachePolicy")()
+
+	query := `
+	UPDATE features
+	SET cache_policy = $1, cache_ttl_seconds = NULLIF($2, 0)
+	WHERE feature_id = $3
+	`
+
+	if cmdTag, err := r.pool.Exec(ctx, query, policy, ttlSeconds, featureID); err != nil {
+		return err
+	} else if cmdTag.RowsAffected() != 1 {
+		return errFeatureNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresFeatureRepository) SetFeatureRotationMode(ctx context.Context, featureID int, mode string) error {
+	defer metrics.ObserveQuery("feature", "SetFeatureRotationMode")()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if cmdTag, err := tx.Exec(ctx, "UPDATE features SET rotation_mode = $1 WHERE feature_id = $2", mode, featureID); err != nil {
+		return err
+	} else if cmdTag.RowsAffected() != 1 {
+		return errFeatureNotFound
+	}
+
+	if mode == models.RotationModeNone {
+		query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM banner_tag bt
+			INNER JOIN banners b ON b.banner_id = bt.banner_id
+			WHERE b.feature_id = $1
+			GROUP BY bt.tag_id
+			HAVING COUNT(*) > 1
+		)
+		`
+
+		var shared bool
+		if err := tx.QueryRow(ctx, query, featureID).Scan(&shared); err != nil {
+			return err
+		}
+		if shared {
+			return apperrors.Conflict(i18n.RotationInUse, nil)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresFeatureRepository) PreviewFeatureDeletion(ctx context.Context, featureID int) (*models.DeletePreview, error) {
+	defer metrics.ObserveQuery("feature", "PreviewFeatureDeletion")()
+
+	query := `
+	SELECT
+		(SELECT COUNT(*) FROM banners WHERE feature_id = $1),
+		(SELECT COUNT(*) FROM banner_tag bt INNER JOIN banners b ON b.banner_id = bt.banner_id WHERE b.feature_id = $1)
+	FROM features
+	WHERE feature_id = $1
+	`
+
+	preview := &models.DeletePreview{}
+	if err := r.pool.QueryRow(ctx, query, featureID).Scan(&preview.Banners, &preview.BannerTags); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errFeatureNotFound
+		}
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+func (r *PostgresFeatureRepository) DeleteFeature(ctx context.Context, featureID int, author string) error {
+	defer metrics.ObserveQuery("feature", "DeleteFeature")()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	banners, err := txrepo.LockBanners(ctx, tx, "b.feature_id = $1", featureID)
+	if err != nil {
+		return err
+	}
+
+	if cmdTag, err := tx.Exec(ctx, "DELETE FROM features WHERE feature_id = $1", featureID); err != nil {
+		return err
+	} else if cmdTag.RowsAffected() != 1 {
+		return errFeatureNotFound
+	}
+
+	for _, banner := range banners {
+		if err := txrepo.RecordBannerChange(ctx, tx, author, models.AuditActionBannerDelete, banner.BannerID, banner, nil); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,132 @@
+package jobrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"banner-service/internal/apperrors"
+	"banner-service/internal/i18n"
+	"banner-service/internal/metrics"
+	"banner-service/internal/models"
+	txrepo "banner-service/internal/repositories/tx"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const jobSelectColumns = `job_id, kind, COALESCE(feature_id, 0), COALESCE(tag_id, 0), status, total, processed, error, author, request_id, created_at, updated_at`
+
+var errJobNotFound = apperrors.NotFound(i18n.JobNotFound)
+
+type PostgresJobRepository struct {
+	pool txrepo.Pool
+}
+
+func NewPostgresJobRepository(pool txrepo.Pool) *PostgresJobRepository {
+	return &PostgresJobRepository{
+		pool: pool,
+	}
+}
+
+func (r *PostgresJobRepository) CreateJob(ctx context.Context, job *models.Job) (int, error) {
+	defer metrics.ObserveQuery("job", "CreateJob")()
+
+	query := `
+	INSERT INTO jobs (kind, feature_id, tag_id, status, author, request_id, created_at, updated_at)
+	VALUES ($1, NULLIF($2, 0), NULLIF($3, 0), $4, $5, $6, $7, $7)
+	RETURNING job_id
+	`
+
+	var jobID int
+	if err := r.pool.QueryRow(ctx, query, job.Kind, job.FeatureID, job.TagID, models.JobStatusPending, job.Author, job.RequestID, time.Now()).Scan(&jobID); err != nil {
+		return 0, err
+	}
+
+	return jobID, nil
+}
+
+func (r *PostgresJobRepository) GetJob(ctx context.Context, jobID int) (*models.Job, error) {
+	defer metrics.ObserveQuery("job", "GetJob")()
+
+	query := `SELECT ` + jobSelectColumns + ` FROM jobs WHERE job_id = $1`
+
+	job, err := scanJob(r.pool.QueryRow(ctx, query, jobID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errJobNotFound
+	}
+
+	return job, err
+}
+
+func (r *PostgresJobRepository) ClaimJob(ctx context.Context, staleAfter time.Duration) (*models.Job, error) {
+	defer metrics.ObserveQuery("job", "ClaimJob")()
+
+	query := `
+	UPDATE jobs
+	SET status = $1, updated_at = $2
+	WHERE job_id = (
+		SELECT job_id
+		FROM jobs
+		WHERE status = $3 OR (status = $1 AND updated_at < $4)
+		ORDER BY job_id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING ` + jobSelectColumns
+
+	now := time.Now()
+	job, err := scanJob(r.pool.QueryRow(ctx, query, models.JobStatusRunning, now, models.JobStatusPending, now.Add(-staleAfter)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	return job, err
+}
+
+func (r *PostgresJobRepository) UpdateJobProgress(ctx context.Context, jobID, total, processed int) error {
+	defer metrics.ObserveQuery("job", "UpdateJobProgress")()
+
+	query := `
+	UPDATE jobs
+	SET total = $1, processed = $2, updated_at = $3
+	WHERE job_id = $4
+	`
+
+	_, err := r.pool.Exec(ctx, query, total, processed, time.Now(), jobID)
+	return err
+}
+
+func (r *PostgresJobRepository) FinishJob(ctx context.Context, jobID int, status, errMsg string) error {
+	defer metrics.ObserveQuery("job", "FinishJob")()
+
+	query := `
+	UPDATE jobs
+	SET status = $1, error = $2, updated_at = $3
+	WHERE job_id = $4
+	`
+
+	_, err := r.pool.Exec(ctx, query, status, errMsg, time.Now(), jobID)
+	return err
+}
+
+func scanJob(row pgx.Row) (*models.Job, error) {
+	job := &models.Job{}
+	if err := row.Scan(
+		&job.JobID,
+		&job.Kind,
+		&job.FeatureID,
+		&job.TagID,
+		&job.Status,
+		&job.Total,
+		&job.Processed,
+		&job.Error,
+		&job.Author,
+		&job.RequestID,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
@@ -0,0 +1,90 @@
+package statsrepo
+
+import (
+	"context"
+	"time"
+
+	"banner-service/internal/metrics"
+	"banner-service/internal/models"
+	txrepo "banner-service/internal/repositories/tx"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type PostgresStatsRepository struct {
+	pool txrepo.Pool
+}
+
+func NewPostgresStatsRepository(pool txrepo.Pool) *PostgresStatsRepository {
+	return &PostgresStatsRepository{
+		pool: pool,
+	}
+}
+
+func (r *PostgresStatsRepository) AddBannerStats(ctx context.Context, deltas []*models.BannerStatsDelta) error {
+	defer metrics.ObserveQuery("stats", "AddBannerStats")()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+	INSERT INTO banner_stats (banner_id, bucket, impressions, clicks)
+	SELECT $1::integer, $2::timestamptz, $3::bigint, $4::bigint
+	WHERE EXISTS (SELECT 1 FROM banners WHERE banner_id = $1)
+	ON CONFLICT (banner_id, bucket) DO UPDATE
+	SET impressions = banner_stats.impressions + EXCLUDED.impressions, clicks = banner_stats.clicks + EXCLUDED.clicks
+	`
+
+	batch := &pgx.Batch{}
+	for _, delta := range deltas {
+		batch.Queue(query, delta.BannerID, delta.Bucket, delta.Impressions, delta.Clicks)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	for range deltas {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return err
+		}
+	}
+	if err := results.Close(); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresStatsRepository) GetBannerStats(ctx context.Context, bannerID int, from, to time.Time, granularity string) ([]models.BannerStatsPoint, error) {
+	defer metrics.ObserveQuery("stats", "GetBannerStats")()
+
+	query := `
+	SELECT date_trunc($2, bucket AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS period, SUM(impressions)::bigint, SUM(clicks)::bigint
+	FROM banner_stats
+	WHERE banner_id = $1 AND bucket >= $3 AND bucket < $4
+	GROUP BY period
+	ORDER BY period
+	`
+
+	rows, err := r.pool.Query(ctx, query, bannerID, granularity, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := make([]models.BannerStatsPoint, 0)
+	for rows.Next() {
+		var point models.BannerStatsPoint
+		if err := rows.Scan(&point.Bucket, &point.Impressions, &point.Clicks); err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
@@ -0,0 +1,158 @@
+package tagrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"banner-service/internal/apperrors"
+	"banner-service/internal/i18n"
+	"banner-service/internal/metrics"
+	"banner-service/internal/models"
+	txrepo "banner-service/internal/repositories/tx"
+
+	"github.com/jackc/pgx/v4"
+)
+
+var errTagNotFound = apperrors.NotFound(i18n.TagNotFound)
+
+type PostgresTagRepository struct {
+	pool txrepo.Pool
+}
+
+func NewPostgresTagRepository(pool txrepo.Pool) *PostgresTagRepository {
+	return &PostgresTagRepository{
+		pool: pool,
+	}
+}
+
+func (r *PostgresTagRepository) GetTags(ctx context.Context, limit, offset int) ([]*models.Tag, error) {
+	defer metrics.ObserveQuery("tag", "GetTags")()
+
+	var queryParams []interface{}
+	query := `
+	SELECT tag_id, name
+	FROM tags
+	ORDER BY tag_id
+	`
+	if limit > 0 && offset >= 0 {
+		query += fmt.Sprintf("LIMIT $%d OFFSET $%d", len(queryParams)+1, len(queryParams)+2)
+		queryParams = append(queryParams, limit, offset)
+	}
+
+	rows, err := r.pool.Query(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make([]*models.Tag, 0)
+	for rows.Next() {
+		tag := &models.Tag{}
+		if err := rows.Scan(&tag.TagID, &tag.Name); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (r *PostgresTagRepository) GetTag(ctx context.Context, tagID int) (*models.Tag, error) {
+	defer metrics.ObserveQuery("tag", "GetTag")()
+
+	tag := &models.Tag{}
+	if err := r.pool.QueryRow(ctx, "SELECT tag_id, name FROM tags WHERE tag_id = $1", tagID).Scan(
+		&tag.TagID,
+		&tag.Name,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errTagNotFound
+		}
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (r *PostgresTagRepository) CreateTag(ctx context.Context, tag *models.Tag) (int, error) {
+	defer metrics.ObserveQuery("tag", "CreateTag")()
+
+	var tagID int
+	if err := r.pool.QueryRow(ctx, "INSERT INTO tags (name) VALUES ($1) RETURNING tag_id", tag.Name).Scan(&tagID); err != nil {
+		return 0, err
+	}
+
+	return tagID, nil
+}
+
+func (r *PostgresTagRepository) UpdateTag(ctx context.Context, tagID int, tag *models.Tag) error {
+	defer metrics.ObserveQuery("tag", "UpdateTag")()
+
+	if cmdTag, err := r.pool.Exec(ctx, "UPDATE tags SET name = $1 WHERE tag_id = $2", tag.Name, tagID); err != nil {
+		return err
+	} else if cmdTag.RowsAffected() != 1 {
+		return errTagNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresTagRepository) PreviewTagDeletion(ctx context.Context, tagID int) (*models.DeletePreview, error) {
+	defer metrics.ObserveQuery("tag", "PreviewTagDeletion")()
+
+	query := `
+	SELECT
+		(SELECT COUNT(*) FROM banner_tag WHERE tag_id = $1),
+		(SELECT COUNT(*) FROM banner_tag bt
+		 WHERE bt.tag_id = $1
+		 AND NOT EXISTS (SELECT 1 FROM banner_tag other WHERE other.banner_id = bt.banner_id AND other.tag_id <> $1))
+	FROM tags
+	WHERE tag_id = $1
+	`
+
+	preview := &models.DeletePreview{}
+	if err := r.pool.QueryRow(ctx, query, tagID).Scan(&preview.BannerTags, &preview.UntaggedBanners); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errTagNotFound
+		}
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+func (r *PostgresTagRepository) DeleteTag(ctx context.Context, tagID int, author string) error {
+	defer metrics.ObserveQuery("tag", "DeleteTag")()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	banners, err := txrepo.LockBanners(ctx, tx, "b.banner_id IN (SELECT banner_id FROM banner_tag WHERE tag_id = $1)", tagID)
+	if err != nil {
+		return err
+	}
+
+	if cmdTag, err := tx.Exec(ctx, "DELETE FROM tags WHERE tag_id = $1", tagID); err != nil {
+		return err
+	} else if cmdTag.RowsAffected() != 1 {
+		return errTagNotFound
+	}
+
+	for _, before := range banners {
+		after := *before
+		after.TagIDs = slices.DeleteFunc(slices.Clone(before.TagIDs), func(id int) bool { return id == tagID })
+		if err := txrepo.RecordBannerChange(ctx, tx, author, models.AuditActionBannerUpdate, before.BannerID, before, &after); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,143 @@
+package txrepo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"banner-service/internal/logging"
+	"banner-service/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+var ignoredBannerFields = []string{"banner_id", "created_at", "updated_at"}
+
+type Pool interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+func LockBanners(ctx context.Context, tx pgx.Tx, condition string, args ...interface{}) ([]*models.Banner, error) {
+	query := `
+	SELECT b.banner_id, b.feature_id, b.content, b.is_active, b.active_from, b.active_until, b.weight, b.created_at, b.updated_at,
+		COALESCE((SELECT array_agg(bt.tag_id ORDER BY bt.tag_id) FROM banner_tag bt WHERE bt.banner_id = b.banner_id), '{}')
+	FROM banners b
+	WHERE ` + condition + `
+	ORDER BY b.banner_id
+	FOR UPDATE
+	`
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	banners := make([]*models.Banner, 0)
+	for rows.Next() {
+		banner := &models.Banner{}
+		if err := rows.Scan(
+			&banner.BannerID,
+			&banner.FeatureID,
+			&banner.Content,
+			&banner.IsActive,
+			&banner.ActiveFrom,
+			&banner.ActiveUntil,
+			&banner.Weight,
+			&banner.CreatedAt,
+			&banner.UpdatedAt,
+			&banner.TagIDs,
+		); err != nil {
+			return nil, err
+		}
+		banners = append(banners, banner)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return banners, nil
+}
+
+func RecordBannerChange(ctx context.Context, tx pgx.Tx, actor, action string, bannerID int, before, after *models.Banner) error {
+	changes, err := bannerChanges(before, after)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO audit_log (actor, action, banner_id, changes, request_id, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err = tx.Exec(ctx, query, actor, action, bannerID, changes, logging.RequestID(ctx), time.Now())
+	return err
+}
+
+func bannerChanges(before, after *models.Banner) ([]byte, error) {
+	beforeFields, err := bannerFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := bannerFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]models.AuditChange)
+	for field, value := range beforeFields {
+		if !bytes.Equal(value, afterFields[field]) {
+			changes[field] = models.AuditChange{Before: value, After: afterFields[field]}
+		}
+	}
+	for field, value := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			changes[field] = models.AuditChange{After: value}
+		}
+	}
+
+	return json.Marshal(changes)
+}
+
+func bannerFields(banner *models.Banner) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if banner == nil {
+		return fields, nil
+	}
+
+	snapshot := *banner
+	snapshot.TagIDs = slices.Clone(banner.TagIDs)
+	slices.Sort(snapshot.TagIDs)
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for _, field := range ignoredBannerFields {
+		delete(fields, field)
+	}
+
+	for field, value := range fields {
+		decoder := json.NewDecoder(bytes.NewReader(value))
+		decoder.UseNumber()
+
+		var v interface{}
+		if err := decoder.Decode(&v); err != nil {
+			return nil, err
+		}
+		if fields[field], err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,111 @@
+package txrepo
+
+import (
+	"banner-service/internal/models"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+func decodeChanges(t *testing.T, data []byte) map[string]models.AuditChange {
+	t.Helper()
+
+	var changes map[string]models.AuditChange
+	if err := json.Unmarshal(data, &changes); err != nil {
+		t.Fatalf("decode changes %s: %v", data, err)
+	}
+
+	return changes
+}
+
+func changedFields(changes map[string]models.AuditChange) []string {
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+
+	return fields
+}
+
+func auditBanner() *models.Banner {
+	return &models.Banner{
+		BannerID:  1,
+		FeatureID: 2,
+		TagIDs:    []int{3, 1, 2},
+		Content:   json.RawMessage(`{"title": "sale", "views": 12345678901234567890}`),
+		IsActive:  true,
+		Weight:    1,
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestBannerChangesCreate(t *testing.T) {
+	data, err := bannerChanges(nil, auditBanner())
+	if err != nil {
+		t.Fatalf("bannerChanges: %v", err)
+	}
+
+	changes := decodeChanges(t, data)
+	if want := []string{"content", "feature_id", "is_active", "tag_ids", "weight"}; !slices.Equal(changedFields(changes), want) {
+		t.Errorf("changed fields = %v, want %v", changedFields(changes), want)
+	}
+	if got := string(changes["tag_ids"].After); got != "[1,2,3]" {
+		t.Errorf("tag_ids after = %s, want sorted [1,2,3]", got)
+	}
+	if got := string(changes["content"].After); got != `{"title":"sale","views":12345678901234567890}` {
+		t.Errorf("content after = %s, want the exact number preserved", got)
+	}
+}
+
+func TestBannerChangesIgnoresOrderingAndTimestamps(t *testing.T) {
+	before := auditBanner()
+	after := auditBanner()
+	after.TagIDs = []int{1, 2, 3}
+	after.Content = json.RawMessage(`{"views":12345678901234567890,"title":"sale"}`)
+	after.UpdatedAt = time.Now()
+
+	data, err := bannerChanges(before, after)
+	if err != nil {
+		t.Fatalf("bannerChanges: %v", err)
+	}
+
+	if changes := decodeChanges(t, data); len(changes) != 0 {
+		t.Errorf("changes = %v, want none", changes)
+	}
+}
+
+func TestBannerChangesUpdateAndDelete(t *testing.T) {
+	before := auditBanner()
+	after := auditBanner()
+	after.Weight = 5
+	after.Content = json.RawMessage(`{"title": "sale", "views": 12345678901234567891}`)
+
+	data, err := bannerChanges(before, after)
+	if err != nil {
+		t.Fatalf("bannerChanges: %v", err)
+	}
+
+	changes := decodeChanges(t, data)
+	if want := []string{"content", "weight"}; !slices.Equal(changedFields(changes), want) {
+		t.Fatalf("changed fields = %v, want %v", changedFields(changes), want)
+	}
+	if string(changes["weight"].Before) != "1" || string(changes["weight"].After) != "5" {
+		t.Errorf("weight change = %s -> %s, want 1 -> 5", changes["weight"].Before, changes["weight"].After)
+	}
+
+	data, err = bannerChanges(before, nil)
+	if err != nil {
+		t.Fatalf("bannerChanges: %v", err)
+	}
+
+	changes = decodeChanges(t, data)
+	if want := []string{"content", "feature_id", "is_active", "tag_ids", "weight"}; !slices.Equal(changedFields(changes), want) {
+		t.Errorf("changed fields on delete = %v, want %v", changedFields(changes), want)
+	}
+	if after := changes["is_active"].After; after != nil && string(after) != "null" {
+		t.Errorf("is_active after delete = %s, want null", after)
+	}
+}
@@ -0,0 +1,184 @@
+package userrepo
+
+import (
+	"context"
+	"errors"
+
+	"banner-service/internal/apperrors"
+	"banner-service/internal/i18n"
+	"banner-service/internal/metrics"
+	"banner-service/internal/models"
+	txrepo "banner-service/internal/repositories/tx"
+	"banner-service/internal/utils"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const userSelectQuery = `
+	SELECT u.user_id, COALESCE(u.login, ''), u.password_hash, u.is_admin, u.role,
+		COALESCE(array_agg(uf.feature_id ORDER BY uf.feature_id) FILTER (WHERE uf.feature_id IS NOT NULL), '{}')
+	FROM users u
+	LEFT JOIN user_features uf ON u.user_id = uf.user_id
+	`
+
+var errUserNotFound = apperrors.NotFound(i18n.UserNotFound)
+
+type PostgresUserRepository struct {
+	pool txrepo.Pool
+}
+
+func NewPostgresUserRepository(pool txrepo.Pool) *PostgresUserRepository {
+	return &PostgresUserRepository{
+		pool: pool,
+	}
+}
+
+func (r *PostgresUserRepository) GetUserByID(ctx context.Context, userID int) (*models.User, error) {
+	defer metrics.ObserveQuery("user", "GetUserByID")()
+
+	query := userSelectQuery + `
+	WHERE u.user_id = $1
+	GROUP BY u.user_id
+	`
+
+	return scanUser(r.pool.QueryRow(ctx, query, userID))
+}
+
+func (r *PostgresUserRepository) GetUserByLogin(ctx context.Context, login string) (*models.User, error) {
+	defer metrics.ObserveQuery("user", "GetUserByLogin")()
+
+	query := userSelectQuery + `
+	WHERE u.login = $1
+	GROUP BY u.user_id
+	`
+
+	return scanUser(r.pool.QueryRow(ctx, query, login))
+}
+
+func (r *PostgresUserRepository) GetUserByToken(ctx context.Context, tokenHash string) (*models.User, error) {
+	defer metrics.ObserveQuery("user", "GetUserByToken")()
+
+	query := userSelectQuery + `
+	WHERE u.token = $1 AND u.token <> ''
+	GROUP BY u.user_id
+	`
+
+	return scanUser(r.pool.QueryRow(ctx, query, tokenHash))
+}
+
+func (r *PostgresUserRepository) CreateUser(ctx context.Context, user *models.User, tokenHash string) (int, error) {
+	defer metrics.ObserveQuery("user", "CreateUser")()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+	INSERT INTO users (login, password_hash, token, is_admin, role)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING user_id
+	`
+
+	var userID int
+	if err := tx.QueryRow(ctx, query, user.Login, user.PasswordHash, tokenHash, user.IsAdmin, user.Role).Scan(&userID); err != nil {
+		if utils.IsUniqueViolation(err) {
+			return 0, apperrors.Conflict(i18n.UserExists, err)
+		}
+		return 0, err
+	}
+
+	if err := insertUserFeatures(ctx, tx, userID, user.FeatureIDs); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+func (r *PostgresUserRepository) UpsertAdmin(ctx context.Context, user *models.User) error {
+	defer metrics.ObserveQuery("user", "UpsertAdmin")()
+
+	query := `
+	INSERT INTO users (login, password_hash, is_admin, role)
+	VALUES ($1, $2, TRUE, $3)
+	ON CONFLICT (login) DO UPDATE SET password_hash = EXCLUDED.password_hash, is_admin = TRUE, role = EXCLUDED.role
+	`
+
+	_, err := r.pool.Exec(ctx, query, user.Login, user.PasswordHash, user.Role)
+	return err
+}
+
+func (r *PostgresUserRepository) SetUserRole(ctx context.Context, userID int, role string, isAdmin bool, featureIDs []int) error {
+	defer metrics.ObserveQuery("user", "SetUserRole")()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if cmdTag, err := tx.Exec(ctx, "UPDATE users SET role = $1, is_admin = $2 WHERE user_id = $3", role, isAdmin, userID); err != nil {
+		return err
+	} else if cmdTag.RowsAffected() != 1 {
+		return errUserNotFound
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM user_features WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+
+	if err := insertUserFeatures(ctx, tx, userID, featureIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresUserRepository) SetUserToken(ctx context.Context, userID int, tokenHash string) error {
+	defer metrics.ObserveQuery("user", "SetUserToken")()
+
+	if cmdTag, err := r.pool.Exec(ctx, "UPDATE users SET token = NULLIF($1, '') WHERE user_id = $2", tokenHash, userID); err != nil {
+		return err
+	} else if cmdTag.RowsAffected() != 1 {
+		return errUserNotFound
+	}
+
+	return nil
+}
+
+func insertUserFeatures(ctx context.Context, tx pgx.Tx, userID int, featureIDs []int) error {
+	for _, featureID := range featureIDs {
+		if _, err := tx.Exec(ctx, "INSERT INTO user_features (user_id, feature_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", userID, featureID); err != nil {
+			if utils.IsForeignKeyViolation(err) {
+				return apperrors.InvalidField("feature_ids", i18n.FeatureMissing)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scanUser(row pgx.Row) (*models.User, error) {
+	user := &models.User{}
+	if err := row.Scan(
+		&user.UserID,
+		&user.Login,
+		&user.PasswordHash,
+		&user.IsAdmin,
+		&user.Role,
+		&user.FeatureIDs,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errUserNotFound
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,40 @@
+package bannerservice
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/i18n"
+	"banner-service/internal/models"
+	"context"
+)
+
+const defaultAuditLimit = 100
+
+var errAuditRangeInvalid = apperrors.InvalidField("from", i18n.AuditRangeInvalid)
+
+type DBAuditRepository interface {
+	GetAuditEntries(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, error)
+}
+
+type AuditService struct {
+	auditRepo DBAuditRepository
+}
+
+func NewAuditService(auditRepo DBAuditRepository) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+	}
+}
+
+func (s *AuditService) GetAuditEntries(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, errAuditRangeInvalid
+	}
+
+	return s.auditRepo.GetAuditEntries(ctx, filter)
+}
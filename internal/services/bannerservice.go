@@ -1,78 +1,195 @@
 package bannerservice
 
 import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/config"
+	"banner-service/internal/i18n"
 	"banner-service/internal/models"
 	"banner-service/internal/utils"
 	"context"
 	"errors"
+	"hash/fnv"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
 )
 
+const invalidationSlots = 4096
+
+var tracer = otel.Tracer("banner-service/internal/services")
+
+var errBannerNotFound = apperrors.NotFound(i18n.BannerNotFound)
+
 type CacheBannerRepository interface {
-	GetBanner(ctx context.Context, key string) (*models.Banner, error)
-	SetBanner(ctx context.Context, key string, banner *models.Banner, ttl time.Duration) error
+	GetBanner(ctx context.Context, key string) (*models.BannerCacheEntry, error)
+	SetBanner(ctx context.Context, key string, entry *models.BannerCacheEntry, ttl time.Duration) error
+	DeleteBanners(ctx context.Context, keys ...string) error
 }
 
 type DBBannerRepository interface {
-	GetBanner(ctx context.Context, featureID, tagID int, isAdmin bool) (*models.Banner, error)
-	GetBanners(ctx context.Context, featureID, tagID, limit, offset int) ([]*models.Banner, error)
-	CreateBanner(ctx context.Context, banner *models.Banner) (int, error)
-	UpdateBanner(ctx context.Context, bannerID int, banner *models.Banner) error
-	DeleteBanner(ctx context.Context, bannerID int) error
+	GetBannerCandidates(ctx context.Context, featureID, tagID int) (*models.Feature, []*models.Banner, error)
+	GetBannerByID(ctx context.Context, bannerID int) (*models.Banner, error)
+	GetBanners(ctx context.Context, filter models.BannerFilter) ([]*models.Banner, error)
+	CreateBanner(ctx context.Context, banner *models.Banner, author string) (int, error)
+	UpdateBanner(ctx context.Context, bannerID int, banner *models.Banner, author string) error
+	DeleteBanner(ctx context.Context, bannerID int, author string) error
+	GetBannerVersions(ctx context.Context, bannerID int) ([]*models.BannerVersion, error)
+	ActivateBannerVersion(ctx context.Context, bannerID, version int, author string) error
+	CountBanners(ctx context.Context, filter models.BannerFilter) (int, error)
+	DeleteBannersBatch(ctx context.Context, filter models.BannerFilter, batchSize int, author string) ([]*models.Banner, error)
+	GetActiveBannersAfter(ctx context.Context, afterID, limit int) ([]*models.Banner, error)
+}
+
+type DBFeatureRepository interface {
+	GetFeatures(ctx context.Context, limit, offset int) ([]*models.Feature, error)
+	GetFeature(ctx context.Context, featureID int) (*models.Feature, error)
+	CreateFeature(ctx context.Context, feature *models.Feature) (int, error)
+	UpdateFeature(ctx context.Context, featureID int, feature *models.Feature) error
+	SetFeatureCachePolicy(ctx context.Context, featureID int, policy string, ttlSeconds int) error
+	SetFeatureRotationMode(ctx context.Context, featureID int, mode string) error
+	PreviewFeatureDeletion(ctx context.Context, featureID int) (*models.DeletePreview, error)
+	DeleteFeature(ctx context.Context, featureID int, author string) error
+}
+
+type DBTagRepository interface {
+	GetTags(ctx context.Context, limit, offset int) ([]*models.Tag, error)
+	GetTag(ctx context.Context, tagID int) (*models.Tag, error)
+	CreateTag(ctx context.Context, tag *models.Tag) (int, error)
+	UpdateTag(ctx context.Context, tagID int, tag *models.Tag) error
+	PreviewTagDeletion(ctx context.Context, tagID int) (*models.DeletePreview, error)
+	DeleteTag(ctx context.Context, tagID int, author string) error
 }
 
 type BannerService struct {
-	cacheRepo CacheBannerRepository
-	dbRepo    DBBannerRepository
+	cacheRepo      CacheBannerRepository
+	dbRepo         DBBannerRepository
+	featureRepo    DBFeatureRepository
+	experimentRepo DBExperimentRepository
+	cacheCfg       config.CacheConfig
+	loads          singleflight.Group
+	rotations      sync.Map
+	invalidations  invalidationClock
+	logger         *slog.Logger
 }
 
-func NewBannerService(cacheRepo CacheBannerRepository, dbRepo DBBannerRepository) *BannerService {
+func NewBannerService(cacheRepo CacheBannerRepository, dbRepo DBBannerRepository, featureRepo DBFeatureRepository, experimentRepo DBExperimentRepository, cacheCfg config.CacheConfig, logger *slog.Logger) *BannerService {
 	return &BannerService{
-		cacheRepo: cacheRepo,
-		dbRepo:    dbRepo,
+		cacheRepo:      cacheRepo,
+		dbRepo:         dbRepo,
+		featureRepo:    featureRepo,
+		experimentRepo: experimentRepo,
+		cacheCfg:       cacheCfg,
+		logger:         logger,
 	}
 }
 
-func (s *BannerService) GetBanner(ctx context.Context, tagID, featureID int, useLastRevision, isAdmin bool) (*models.Banner, error) {
-	if !useLastRevision {
-		cachedBanner, err := s.cacheRepo.GetBanner(ctx, utils.MakeCacheKey(featureID, tagID))
+func (s *BannerService) GetBanner(ctx context.Context, tagID, featureID int, userID string, useLastRevision, isAdmin bool) (*models.Banner, *models.ExperimentAssignment, error) {
+	ctx, span := tracer.Start(ctx, "BannerService.GetBanner", trace.WithAttributes(attribute.Int("banner.feature_id", featureID), attribute.Int("banner.tag_id", tagID), attribute.Bool("banner.use_last_revision", useLastRevision)))
+	defer span.End()
+
+	key := utils.MakeCacheKey(featureID, tagID)
+
+	if useLastRevision {
+		entry, err := s.loadBanner(ctx, key, featureID, tagID)
 		if err != nil {
-			return nil, err
-		}
-		if cachedBanner != nil {
-			return cachedBanner, nil
+			return nil, nil, err
 		}
+		return s.pickBanner(key, entry, userID, isAdmin)
 	}
 
-	dbBanner, err := s.dbRepo.GetBanner(ctx, featureID, tagID, isAdmin)
+	entry, err := s.cachedEntry(ctx, key, featureID, tagID)
 	if err != nil {
-		return nil, err
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, nil, err
+	}
+
+	return s.pickBanner(key, entry, userID, isAdmin)
+}
+
+func (s *BannerService) CheckBannerServed(ctx context.Context, featureID, tagID, bannerID int) error {
+	ctx, span := tracer.Start(ctx, "BannerService.CheckBannerServed", trace.WithAttributes(attribute.Int("banner.id", bannerID), attribute.Int("banner.feature_id", featureID), attribute.Int("banner.tag_id", tagID)))
+	defer span.End()
+
+	entry, err := s.cachedEntry(ctx, utils.MakeCacheKey(featureID, tagID), featureID, tagID)
+	if err != nil {
+		return err
+	}
+	if banner := findBanner(entry.Banners, bannerID); banner == nil || !banner.IsLive(time.Now()) {
+		return errBannerNotFound
+	}
+
+	return nil
+}
+
+func (s *BannerService) cachedEntry(ctx context.Context, key string, featureID, tagID int) (*models.BannerCacheEntry, error) {
+	span := trace.SpanFromContext(ctx)
+
+	entry, err := s.cacheRepo.GetBanner(ctx, key)
+	if err != nil {
+		s.logger.WarnContext(ctx, "could not read cached banner", "key", key, "error", err)
+		entry = nil
+	}
+	if entry != nil && !s.isStale(entry) {
+		span.SetAttributes(attribute.String("cache.result", "hit"))
+		return entry, nil
+	}
+
+	if entry != nil {
+		span.SetAttributes(attribute.String("cache.result", "stale"))
+		s.loads.DoChan(key, func() (interface{}, error) {
+			return s.loadBanner(ctx, key, featureID, tagID)
+		})
+		return entry, nil
 	}
 
-	if dbBanner != nil {
-		_ = s.cacheRepo.SetBanner(ctx, utils.MakeCacheKey(featureID, tagID), dbBanner, time.Duration(time.Minute*5))
+	span.SetAttributes(attribute.String("cache.result", "miss"))
+	v, err, shared := s.loads.Do(key, func() (interface{}, error) {
+		return s.loadBanner(ctx, key, featureID, tagID)
+	})
+	span.SetAttributes(attribute.Bool("cache.load_shared", shared))
+	if err != nil {
+		return nil, err
 	}
 
-	return dbBanner, nil
+	return v.(*models.BannerCacheEntry), nil
+}
+
+func (s *BannerService) GetBannerByID(ctx context.Context, bannerID int) (*models.Banner, error) {
+	ctx, span := tracer.Start(ctx, "BannerService.GetBannerByID", trace.WithAttributes(attribute.Int("banner.id", bannerID)))
+	defer span.End()
+
+	return s.dbRepo.GetBannerByID(ctx, bannerID)
 }
 
-func (s *BannerService) GetBanners(ctx context.Context, featureID, tagID, limit, offset int) ([]*models.Banner, error) {
-	if featureID == -1 {
-		featureID = 0
+func (s *BannerService) GetBanners(ctx context.Context, filter models.BannerFilter) ([]*models.Banner, error) {
+	ctx, span := tracer.Start(ctx, "BannerService.GetBanners", trace.WithAttributes(attribute.Int("banner.feature_id", filter.FeatureID), attribute.Int("banner.tag_id", filter.TagID)))
+	defer span.End()
+
+	if filter.FeatureID == -1 {
+		filter.FeatureID = 0
 	}
-	if tagID == -1 {
-		tagID = 0
+	if filter.TagID == -1 {
+		filter.TagID = 0
 	}
 
-	if limit <= 0 {
-		limit = 0
+	if filter.Limit <= 0 {
+		filter.Limit = 0
 	}
 
-	if offset < 0 {
-		offset = 0
+	if filter.Offset < 0 {
+		filter.Offset = 0
 	}
 
-	banners, err := s.dbRepo.GetBanners(ctx, featureID, tagID, limit, offset)
+	banners, err := s.dbRepo.GetBanners(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -80,50 +197,351 @@ func (s *BannerService) GetBanners(ctx context.Context, featureID, tagID, limit,
 	return banners, nil
 }
 
-func (s *BannerService) CreateBanner(ctx context.Context, banner *models.Banner) (int, error) {
-	if banner == nil {
-		return 0, errors.New("banner не может быть nil")
+func (s *BannerService) CreateBanner(ctx context.Context, banner *models.Banner, author string) (int, error) {
+	ctx, span := tracer.Start(ctx, "BannerService.CreateBanner")
+	defer span.End()
+
+	if err := validateBanner(banner); err != nil {
+		return 0, err
 	}
-	if len(banner.TagIDs) == 0 {
-		return 0, errors.New("должен быть указан хотя бы один tag_id")
+
+	bannerID, err := s.dbRepo.CreateBanner(ctx, banner, author)
+	if err != nil {
+		return 0, err
 	}
-	if banner.FeatureID == 0 {
-		return 0, errors.New("неверный feature_id")
+
+	s.invalidateBanners(ctx, banner)
+	s.logger.InfoContext(ctx, "banner created", "banner_id", bannerID, "feature_id", banner.FeatureID, "author", author)
+
+	return bannerID, nil
+}
+
+func (s *BannerService) UpdateBanner(ctx context.Context, bannerID int, banner *models.Banner, author string) error {
+	ctx, span := tracer.Start(ctx, "BannerService.UpdateBanner", trace.WithAttributes(attribute.Int("banner.id", bannerID)))
+	defer span.End()
+
+	if err := validateBanner(banner); err != nil {
+		return err
 	}
-	if banner.Content == nil {
-		return 0, errors.New("неверное содержимое баннера")
+
+	oldBanner, err := s.dbRepo.GetBannerByID(ctx, bannerID)
+	if err != nil {
+		return err
 	}
 
-	bannerID, err := s.dbRepo.CreateBanner(ctx, banner)
+	err = s.dbRepo.UpdateBanner(ctx, bannerID, banner, author)
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	return bannerID, nil
+	s.invalidateBanners(ctx, oldBanner, banner)
+	s.logger.InfoContext(ctx, "banner updated", "banner_id", bannerID, "feature_id", banner.FeatureID, "author", author)
+
+	return nil
+}
+
+func (s *BannerService) DeleteBanner(ctx context.Context, bannerID int, author string) error {
+	ctx, span := tracer.Start(ctx, "BannerService.DeleteBanner", trace.WithAttributes(attribute.Int("banner.id", bannerID)))
+	defer span.End()
+
+	oldBanner, err := s.dbRepo.GetBannerByID(ctx, bannerID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.dbRepo.DeleteBanner(ctx, bannerID, author); err != nil {
+		return err
+	}
+
+	s.invalidateBanners(ctx, oldBanner)
+	s.logger.InfoContext(ctx, "banner deleted", "banner_id", bannerID, "author", author)
+
+	return nil
+}
+
+func (s *BannerService) GetBannerVersions(ctx context.Context, bannerID int) ([]*models.BannerVersion, error) {
+	ctx, span := tracer.Start(ctx, "BannerService.GetBannerVersions", trace.WithAttributes(attribute.Int("banner.id", bannerID)))
+	defer span.End()
+
+	return s.dbRepo.GetBannerVersions(ctx, bannerID)
+}
+
+func (s *BannerService) ActivateBannerVersion(ctx context.Context, bannerID, version int, author string) error {
+	ctx, span := tracer.Start(ctx, "BannerService.ActivateBannerVersion", trace.WithAttributes(attribute.Int("banner.id", bannerID), attribute.Int("banner.version", version)))
+	defer span.End()
+
+	if version <= 0 {
+		return apperrors.InvalidField("version", i18n.VersionInvalid)
+	}
+
+	oldBanner, err := s.dbRepo.GetBannerByID(ctx, bannerID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.dbRepo.ActivateBannerVersion(ctx, bannerID, version, author); err != nil {
+		return err
+	}
+
+	newBanner, err := s.dbRepo.GetBannerByID(ctx, bannerID)
+	if err != nil {
+		s.invalidateBanners(ctx, oldBanner)
+		return nil
+	}
+
+	s.invalidateBanners(ctx, oldBanner, newBanner)
+	s.logger.InfoContext(ctx, "banner version activated", "banner_id", bannerID, "version", version, "author", author)
+
+	return nil
 }
 
-func (s *BannerService) UpdateBanner(ctx context.Context, bannerID int, banner *models.Banner) error {
+func (s *BannerService) loadBanner(ctx context.Context, key string, featureID, tagID int) (*models.BannerCacheEntry, error) {
+	ctx, span := tracer.Start(ctx, "BannerService.loadBanner", trace.WithAttributes(attribute.Int("banner.feature_id", featureID), attribute.Int("banner.tag_id", tagID)))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.cacheCfg.LoadTimeout)
+	defer cancel()
+
+	loadedAt := time.Now()
+	feature, banners, err := s.dbRepo.GetBannerCandidates(ctx, featureID, tagID)
+	if feature == nil {
+		feature = defaultFeature(featureID)
+	}
+	if errors.Is(err, apperrors.ErrNotFound) {
+		entry := &models.BannerCacheEntry{NotFound: true}
+		if _, cacheable := s.cacheTTL(feature); cacheable && !s.invalidations.invalidatedSince(key, loadedAt) {
+			_ = s.cacheRepo.SetBanner(ctx, key, entry, s.cacheCfg.NotFoundTTL)
+		}
+		return entry, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	experiment, err := s.experimentRepo.GetCurrentExperiment(ctx, featureID, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &models.BannerCacheEntry{Banners: banners, RotationMode: feature.RotationMode, Experiment: experiment}
+	if ttl, cacheable := s.cacheTTL(feature); cacheable {
+		_ = s.storeBanner(ctx, key, entry, ttl, loadedAt)
+	}
+
+	return entry, nil
+}
+
+func (s *BannerService) featureSettings(ctx context.Context, featureID int) *models.Feature {
+	feature, err := s.featureRepo.GetFeature(ctx, featureID)
+	if err != nil {
+		if !errors.Is(err, apperrors.ErrNotFound) {
+			s.logger.WarnContext(ctx, "could not load feature settings", "feature_id", featureID, "error", err)
+		}
+		return defaultFeature(featureID)
+	}
+
+	return feature
+}
+
+func defaultFeature(featureID int) *models.Feature {
+	return &models.Feature{FeatureID: featureID, CachePolicy: models.CachePolicyDefault, RotationMode: models.RotationModeNone}
+}
+
+func (s *BannerService) cacheTTL(feature *models.Feature) (time.Duration, bool) {
+	switch feature.CachePolicy {
+	case models.CachePolicyNever:
+		return 0, false
+	case models.CachePolicyForever:
+		return 0, true
+	case models.CachePolicyTTL:
+		if feature.CacheTTLSeconds > 0 {
+			return time.Duration(feature.CacheTTLSeconds) * time.Second, true
+		}
+	}
+
+	return s.cacheCfg.TTL, true
+}
+
+func (s *BannerService) storeBanner(ctx context.Context, key string, entry *models.BannerCacheEntry, ttl time.Duration, loadedAt time.Time) error {
+	if s.invalidations.invalidatedSince(key, loadedAt) {
+		s.logger.DebugContext(ctx, "skipped caching banners invalidated during load", "key", key)
+		return nil
+	}
+
+	if ttl > 0 && s.cacheCfg.StaleWhileRevalidate > 0 {
+		entry.ExpiresAt = time.Now().Add(ttl)
+		ttl += s.cacheCfg.StaleWhileRevalidate
+	}
+	for _, banner := range entry.Banners {
+		if banner.ActiveUntil == nil {
+			continue
+		}
+		if remaining := time.Until(*banner.ActiveUntil); remaining > 0 && (ttl == 0 || remaining < ttl) {
+			ttl = remaining
+		}
+	}
+
+	return s.cacheRepo.SetBanner(ctx, key, entry, ttl)
+}
+
+func (s *BannerService) isStale(entry *models.BannerCacheEntry) bool {
+	return s.cacheCfg.StaleWhileRevalidate > 0 && !entry.ExpiresAt.IsZero() && time.Now().After(entry.ExpiresAt)
+}
+
+func (s *BannerService) pickBanner(key string, entry *models.BannerCacheEntry, userID string, isAdmin bool) (*models.Banner, *models.ExperimentAssignment, error) {
+	if entry.NotFound {
+		return nil, nil, errBannerNotFound
+	}
+
+	now := time.Now()
+	candidates := make([]*models.Banner, 0, len(entry.Banners))
+	for _, banner := range entry.Banners {
+		if banner.IsLive(now) {
+			candidates = append(candidates, banner)
+		}
+	}
+	if len(candidates) == 0 && isAdmin {
+		candidates = entry.Banners
+	}
+	if len(candidates) == 0 {
+		return nil, nil, errBannerNotFound
+	}
+
+	if experiment := entry.Experiment; experiment != nil {
+		switch {
+		case experiment.Status == models.ExperimentStatusConcluded && experiment.WinnerBannerID != 0:
+			if banner := findBanner(candidates, experiment.WinnerBannerID); banner != nil {
+				return banner, nil, nil
+			}
+		case experiment.Status == models.ExperimentStatusRunning && userID != "":
+			variantID := assignVariant(experiment, userID)
+			if banner := findBanner(candidates, variantID); banner != nil {
+				return banner, &models.ExperimentAssignment{ExperimentID: experiment.ExperimentID, BannerID: variantID}, nil
+			}
+		}
+	}
+
+	return s.rotateBanner(key, entry.RotationMode, candidates), nil, nil
+}
+
+func findBanner(banners []*models.Banner, bannerID int) *models.Banner {
+	for _, banner := range banners {
+		if banner.BannerID == bannerID {
+			return banner
+		}
+	}
+
+	return nil
+}
+
+func (s *BannerService) rotateBanner(key, mode string, candidates []*models.Banner) *models.Banner {
+	switch mode {
+	case models.RotationModeWeighted:
+		total := 0
+		for _, banner := range candidates {
+			total += max(banner.Weight, 1)
+		}
+		n := rand.Intn(total)
+		for _, banner := range candidates {
+			if n -= max(banner.Weight, 1); n < 0 {
+				return banner
+			}
+		}
+	case models.RotationModeRoundRobin:
+		counter, _ := s.rotations.LoadOrStore(key, new(atomic.Uint64))
+		n := counter.(*atomic.Uint64).Add(1) - 1
+		return candidates[n%uint64(len(candidates))]
+	}
+
+	return candidates[0]
+}
+
+func validateBanner(banner *models.Banner) error {
 	if banner == nil {
-		return errors.New("banner не может быть nil")
+		return apperrors.Validation(i18n.InvalidRequest)
+	}
+
+	var details []apperrors.FieldError
+	if banner.FeatureID <= 0 {
+		details = append(details, apperrors.FieldError{Field: "feature_id", Message: i18n.FeatureIDInvalid})
 	}
 	if len(banner.TagIDs) == 0 {
-		return errors.New("должен быть указан хотя бы один tag_id")
+		details = append(details, apperrors.FieldError{Field: "tag_ids", Message: i18n.TagIDsRequired})
 	}
-	if banner.FeatureID == 0 {
-		return errors.New("неверный feature_id")
+	seen := make(map[int]struct{}, len(banner.TagIDs))
+	for _, tagID := range banner.TagIDs {
+		if _, ok := seen[tagID]; ok || tagID <= 0 {
+			details = append(details, apperrors.FieldError{Field: "tag_ids", Message: i18n.TagIDsInvalid})
+			break
+		}
+		seen[tagID] = struct{}{}
 	}
 	if banner.Content == nil {
-		return errors.New("неверное содержимое баннера")
+		details = append(details, apperrors.FieldError{Field: "content", Message: i18n.ContentInvalid})
+	}
+	if banner.Weight <= 0 {
+		details = append(details, apperrors.FieldError{Field: "weight", Message: i18n.WeightInvalid})
+	}
+	if banner.ActiveFrom != nil && banner.ActiveUntil != nil && !banner.ActiveUntil.After(*banner.ActiveFrom) {
+		details = append(details, apperrors.FieldError{Field: "active_until", Message: i18n.ActiveUntilInvalid})
 	}
 
-	err := s.dbRepo.UpdateBanner(ctx, bannerID, banner)
-	if err != nil {
-		return err
+	if len(details) > 0 {
+		return apperrors.Validation(i18n.InvalidRequest, details...)
 	}
 
 	return nil
 }
 
-func (s *BannerService) DeleteBanner(ctx context.Context, bannerID int) error {
-	return s.dbRepo.DeleteBanner(ctx, bannerID)
+func (s *BannerService) invalidateBanners(ctx context.Context, banners ...*models.Banner) {
+	seen := make(map[string]struct{})
+	var keys []string
+	for _, banner := range banners {
+		for _, tagID := range banner.TagIDs {
+			key := utils.MakeCacheKey(banner.FeatureID, tagID)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			keys = append(keys, key)
+		}
+	}
+
+	s.forget(keys)
+	if err := s.cacheRepo.DeleteBanners(ctx, keys...); err != nil {
+		s.logger.ErrorContext(ctx, "could not invalidate cached banners", "keys", keys, "error", err)
+		return
+	}
+	s.logger.DebugContext(ctx, "invalidated cached banners", "keys", keys)
+}
+
+func (s *BannerService) MarkInvalidated(keys []string) {
+	s.forget(keys)
+}
+
+func (s *BannerService) forget(keys []string) {
+	s.invalidations.touch(keys...)
+	for _, key := range keys {
+		s.rotations.Delete(key)
+	}
+}
+
+type invalidationClock [invalidationSlots]atomic.Int64
+
+func (c *invalidationClock) touch(keys ...string) {
+	now := time.Now().UnixNano()
+	for _, key := range keys {
+		c[keySlot(key, invalidationSlots)].Store(now)
+	}
+}
+
+func (c *invalidationClock) invalidatedSince(key string, at time.Time) bool {
+	return c[keySlot(key, invalidationSlots)].Load() >= at.UnixNano()
+}
+
+func keySlot(key string, slots uint32) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+
+	return h.Sum32() % slots
 }
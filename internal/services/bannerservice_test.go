@@ -0,0 +1,573 @@
+package bannerservice
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/config"
+	"banner-service/internal/models"
+	"banner-service/internal/utils"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+type fakeCacheRepo struct {
+	mu      sync.Mutex
+	entries map[string]*models.BannerCacheEntry
+	ttls    map[string]time.Duration
+	deleted []string
+}
+
+func newFakeCacheRepo() *fakeCacheRepo {
+	return &fakeCacheRepo{
+		entries: make(map[string]*models.BannerCacheEntry),
+		ttls:    make(map[string]time.Duration),
+	}
+}
+
+func (c *fakeCacheRepo) GetBanner(ctx context.Context, key string) (*models.BannerCacheEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entries[key], nil
+}
+
+func (c *fakeCacheRepo) SetBanner(ctx context.Context, key string, entry *models.BannerCacheEntry, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry
+	c.ttls[key] = ttl
+	return nil
+}
+
+func (c *fakeCacheRepo) DeleteBanners(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	c.deleted = append(c.deleted, keys...)
+	return nil
+}
+
+type fakeBannerRepo struct {
+	DBBannerRepository
+
+	mu             sync.Mutex
+	feature        *models.Feature
+	banners        []*models.Banner
+	candidateCalls int
+	versions       map[int]*models.Banner
+	onLoad         func()
+}
+
+func (r *fakeBannerRepo) GetBannerCandidates(ctx context.Context, featureID, tagID int) (*models.Feature, []*models.Banner, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.candidateCalls++
+	if r.onLoad != nil {
+		r.onLoad()
+	}
+	if len(r.banners) == 0 {
+		return r.feature, nil, errBannerNotFound
+	}
+
+	return r.feature, r.banners, nil
+}
+
+func (r *fakeBannerRepo) GetBannerByID(ctx context.Context, bannerID int) (*models.Banner, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, banner := range r.banners {
+		if banner.BannerID == bannerID {
+			return banner, nil
+		}
+	}
+
+	return nil, errBannerNotFound
+}
+
+func (r *fakeBannerRepo) ActivateBannerVersion(ctx context.Context, bannerID, version int, author string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	restored, ok := r.versions[version]
+	if !ok {
+		return apperrors.NotFound("version")
+	}
+	for i, banner := range r.banners {
+		if banner.BannerID == bannerID {
+			r.banners[i] = restored
+		}
+	}
+
+	return nil
+}
+
+type fakeExperimentRepo struct {
+	DBExperimentRepository
+
+	experiment *models.Experiment
+}
+
+func (r *fakeExperimentRepo) GetCurrentExperiment(ctx context.Context, featureID, tagID int) (*models.Experiment, error) {
+	return r.experiment, nil
+}
+
+func newTestBannerService(cacheRepo *fakeCacheRepo, dbRepo *fakeBannerRepo, experiment *models.Experiment) *BannerService {
+	cfg := config.CacheConfig{
+		TTL:         time.Minute,
+		NotFoundTTL: 30 * time.Second,
+		LoadTimeout: time.Second,
+	}
+
+	return NewBannerService(cacheRepo, dbRepo, nil, &fakeExperimentRepo{experiment: experiment}, cfg, testLogger)
+}
+
+func testBanner(bannerID int, isActive bool, tagIDs ...int) *models.Banner {
+	return &models.Banner{
+		BannerID:  bannerID,
+		FeatureID: 1,
+		TagIDs:    tagIDs,
+		Content:   []byte(`{"title":"banner ` + strconv.Itoa(bannerID) + `"}`),
+		IsActive:  isActive,
+		Weight:    models.DefaultBannerWeight,
+	}
+}
+
+func TestActivateBannerVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     int
+		wantErr     error
+		wantTags    []int
+		invalidated []int
+	}{
+		{"restores an older version", 1, nil, []int{3}, []int{1, 2, 3}},
+		{"rejects version zero", 0, apperrors.ErrValidation, []int{1, 2}, nil},
+		{"unknown version", 9, apperrors.ErrNotFound, []int{1, 2}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheRepo := newFakeCacheRepo()
+			dbRepo := &fakeBannerRepo{
+				banners:  []*models.Banner{testBanner(1, true, 1, 2)},
+				versions: map[int]*models.Banner{1: testBanner(1, true, 3)},
+			}
+			s := newTestBannerService(cacheRepo, dbRepo, nil)
+
+			if err := s.ActivateBannerVersion(context.Background(), 1, tt.version, "admin"); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ActivateBannerVersion(%d) = %v, want %v", tt.version, err, tt.wantErr)
+			}
+
+			banner, err := dbRepo.GetBannerByID(context.Background(), 1)
+			if err != nil {
+				t.Fatalf("GetBannerByID: %v", err)
+			}
+			if !slices.Equal(banner.TagIDs, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", banner.TagIDs, tt.wantTags)
+			}
+
+			var want []string
+			for _, tagID := range tt.invalidated {
+				want = append(want, utils.MakeCacheKey(1, tagID))
+			}
+			got := slices.Clone(cacheRepo.deleted)
+			slices.Sort(got)
+			if !slices.Equal(got, want) {
+				t.Errorf("invalidated keys = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func scheduledBanner(activeFrom, activeUntil time.Duration) *models.Banner {
+	banner := testBanner(1, true, 1)
+	now := time.Now()
+	if activeFrom != 0 {
+		from := now.Add(activeFrom)
+		banner.ActiveFrom = &from
+	}
+	if activeUntil != 0 {
+		until := now.Add(activeUntil)
+		banner.ActiveUntil = &until
+	}
+
+	return banner
+}
+
+func TestGetBannerVisibility(t *testing.T) {
+	tests := []struct {
+		name    string
+		banner  *models.Banner
+		isAdmin bool
+		wantErr error
+	}{
+		{"active banner for user", testBanner(1, true, 1), false, nil},
+		{"inactive banner for user", testBanner(1, false, 1), false, apperrors.ErrNotFound},
+		{"inactive banner for admin", testBanner(1, false, 1), true, nil},
+		{"scheduled banner for user", scheduledBanner(time.Hour, 0), false, apperrors.ErrNotFound},
+		{"scheduled banner for admin", scheduledBanner(time.Hour, 0), true, nil},
+		{"ended banner for user", scheduledBanner(-2*time.Hour, -time.Hour), false, apperrors.ErrNotFound},
+		{"running campaign for user", scheduledBanner(-time.Hour, time.Hour), false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheRepo := newFakeCacheRepo()
+			s := newTestBannerService(cacheRepo, &fakeBannerRepo{banners: []*models.Banner{tt.banner}}, nil)
+
+			for _, source := range []string{"database", "cache"} {
+				banner, _, err := s.GetBanner(context.Background(), 1, 1, "", false, tt.isAdmin)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetBanner from %s = %v, want %v", source, err, tt.wantErr)
+				}
+				if err == nil && banner.BannerID != tt.banner.BannerID {
+					t.Errorf("GetBanner from %s = banner %d, want %d", source, banner.BannerID, tt.banner.BannerID)
+				}
+			}
+			if entry := cacheRepo.entries[utils.MakeCacheKey(1, 1)]; entry == nil || entry.NotFound {
+				t.Errorf("cache entry = %+v, want the banner cached whatever its visibility", entry)
+			}
+		})
+	}
+}
+
+func TestGetBannerCachesNotFound(t *testing.T) {
+	cacheRepo := newFakeCacheRepo()
+	dbRepo := &fakeBannerRepo{}
+	s := newTestBannerService(cacheRepo, dbRepo, nil)
+
+	for i := 0; i < 3; i++ {
+		if _, _, err := s.GetBanner(context.Background(), 1, 1, "", false, false); !errors.Is(err, apperrors.ErrNotFound) {
+			t.Fatalf("GetBanner #%d = %v, want not found", i, err)
+		}
+	}
+
+	if dbRepo.candidateCalls != 1 {
+		t.Errorf("database was queried %d times, want 1", dbRepo.candidateCalls)
+	}
+	key := utils.MakeCacheKey(1, 1)
+	if entry := cacheRepo.entries[key]; entry == nil || !entry.NotFound {
+		t.Fatalf("cache entry = %+v, want a not-found entry", entry)
+	}
+	if ttl := cacheRepo.ttls[key]; ttl != 30*time.Second {
+		t.Errorf("not-found TTL = %v, want 30s", ttl)
+	}
+}
+
+func TestGetBannerTTLDoesNotOutliveActiveUntil(t *testing.T) {
+	cacheRepo := newFakeCacheRepo()
+	s := newTestBannerService(cacheRepo, &fakeBannerRepo{banners: []*models.Banner{scheduledBanner(-time.Hour, 10*time.Second)}}, nil)
+
+	if _, _, err := s.GetBanner(context.Background(), 1, 1, "", false, false); err != nil {
+		t.Fatalf("GetBanner: %v", err)
+	}
+	if ttl := cacheRepo.ttls[utils.MakeCacheKey(1, 1)]; ttl <= 0 || ttl > 10*time.Second {
+		t.Errorf("cache TTL = %v, want at most the 10s left until active_until", ttl)
+	}
+}
+
+func TestGetBannerCachePolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		feature   *models.Feature
+		wantCalls int
+		wantTTL   time.Duration
+	}{
+		{"default policy", nil, 1, time.Minute},
+		{"feature TTL", &models.Feature{CachePolicy: models.CachePolicyTTL, CacheTTLSeconds: 90}, 1, 90 * time.Second},
+		{"forever", &models.Feature{CachePolicy: models.CachePolicyForever}, 1, 0},
+		{"never", &models.Feature{CachePolicy: models.CachePolicyNever}, 2, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheRepo := newFakeCacheRepo()
+			dbRepo := &fakeBannerRepo{feature: tt.feature, banners: []*models.Banner{testBanner(1, true, 1)}}
+			s := newTestBannerService(cacheRepo, dbRepo, nil)
+
+			for i := 0; i < 2; i++ {
+				if _, _, err := s.GetBanner(context.Background(), 1, 1, "", false, false); err != nil {
+					t.Fatalf("GetBanner: %v", err)
+				}
+			}
+
+			if dbRepo.candidateCalls != tt.wantCalls {
+				t.Errorf("database was queried %d times, want %d", dbRepo.candidateCalls, tt.wantCalls)
+			}
+			ttl, cached := cacheRepo.ttls[utils.MakeCacheKey(1, 1)]
+			if tt.wantTTL < 0 {
+				if cached {
+					t.Errorf("banner was cached for %v, want it not cached", ttl)
+				}
+				return
+			}
+			if !cached || ttl != tt.wantTTL {
+				t.Errorf("cache TTL = %v (cached %v), want %v", ttl, cached, tt.wantTTL)
+			}
+		})
+	}
+}
+
+func TestRotateBannerOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		mode string
+		keys []string
+		want []int
+	}{
+		{"no rotation", models.RotationModeNone, []string{"1:1", "1:1", "1:1"}, []int{1, 1, 1}},
+		{"round-robin", models.RotationModeRoundRobin, []string{"1:1", "1:1", "1:1", "1:1", "1:1", "1:1"}, []int{1, 2, 3, 1, 2, 3}},
+		{"round-robin per pair", models.RotationModeRoundRobin, []string{"1:1", "1:2", "1:1", "1:2", "2:1", "1:1"}, []int{1, 1, 2, 2, 1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestBannerService(newFakeCacheRepo(), &fakeBannerRepo{}, nil)
+			candidates := []*models.Banner{testBanner(1, true, 1), testBanner(2, true, 1), testBanner(3, true, 1)}
+
+			var got []int
+			for _, key := range tt.keys {
+				got = append(got, s.rotateBanner(key, tt.mode, candidates).BannerID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("rotation order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotateBannerForgetsInvalidatedKeys(t *testing.T) {
+	s := newTestBannerService(newFakeCacheRepo(), &fakeBannerRepo{}, nil)
+	candidates := []*models.Banner{testBanner(1, true, 1), testBanner(2, true, 1), testBanner(3, true, 1)}
+
+	for _, key := range []string{"1:1", "1:1", "1:2"} {
+		s.rotateBanner(key, models.RotationModeRoundRobin, candidates)
+	}
+	s.MarkInvalidated([]string{"1:1"})
+
+	if _, ok := s.rotations.Load("1:1"); ok {
+		t.Error("round-robin counter of an invalidated key was kept")
+	}
+	if got := s.rotateBanner("1:1", models.RotationModeRoundRobin, candidates).BannerID; got != 1 {
+		t.Errorf("rotation after invalidation picked banner %d, want 1", got)
+	}
+	if got := s.rotateBanner("1:2", models.RotationModeRoundRobin, candidates).BannerID; got != 2 {
+		t.Errorf("rotation of another key picked banner %d, want 2", got)
+	}
+}
+
+func TestRotateBannerWeighted(t *testing.T) {
+	tests := []struct {
+		name      string
+		weights   []int
+		wantShare float64
+	}{
+		{"equal weights", []int{1, 1}, 0.5},
+		{"heavy second banner", []int{1, 3}, 0.75},
+		{"light second banner", []int{9, 1}, 0.1},
+	}
+
+	const picks = 4000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestBannerService(newFakeCacheRepo(), &fakeBannerRepo{}, nil)
+			var candidates []*models.Banner
+			for i, weight := range tt.weights {
+				banner := testBanner(i+1, true, 1)
+				banner.Weight = weight
+				candidates = append(candidates, banner)
+			}
+
+			counts := make(map[int]int)
+			for i := 0; i < picks; i++ {
+				counts[s.rotateBanner("1:1", models.RotationModeWeighted, candidates).BannerID]++
+			}
+			if share := float64(counts[2]) / picks; share < tt.wantShare-0.05 || share > tt.wantShare+0.05 {
+				t.Errorf("second banner share = %.2f, want about %.2f (counts %v)", share, tt.wantShare, counts)
+			}
+		})
+	}
+}
+
+func TestPickBannerExperiment(t *testing.T) {
+	experiment := func(status string, winnerBannerID int) *models.Experiment {
+		return &models.Experiment{
+			ExperimentID:   7,
+			Status:         status,
+			Variants:       []models.ExperimentVariant{{BannerID: 1, Traffic: 50}, {BannerID: 2, Traffic: 50}},
+			WinnerBannerID: winnerBannerID,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		experiment   *models.Experiment
+		userID       string
+		inactiveID   int
+		wantAssigned bool
+		want         []int
+	}{
+		{"running experiment is sticky for alice", experiment(models.ExperimentStatusRunning, 0), "alice", 0, true, nil},
+		{"running experiment is sticky for bob", experiment(models.ExperimentStatusRunning, 0), "bob", 0, true, nil},
+		{"anonymous user rotates", experiment(models.ExperimentStatusRunning, 0), "", 0, false, []int{1, 2, 1}},
+		{"paused experiment rotates", experiment(models.ExperimentStatusPaused, 0), "alice", 0, false, []int{1, 2, 1}},
+		{"concluded experiment serves the winner", experiment(models.ExperimentStatusConcluded, 2), "alice", 0, false, []int{2, 2, 2}},
+		{"inactive winner falls back to rotation", experiment(models.ExperimentStatusConcluded, 2), "alice", 2, false, []int{1, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestBannerService(newFakeCacheRepo(), &fakeBannerRepo{}, nil)
+			entry := &models.BannerCacheEntry{
+				Banners:      []*models.Banner{testBanner(1, tt.inactiveID != 1, 1), testBanner(2, tt.inactiveID != 2, 1)},
+				RotationMode: models.RotationModeRoundRobin,
+				Experiment:   tt.experiment,
+			}
+
+			want := tt.want
+			if tt.wantAssigned {
+				variant := assignVariant(tt.experiment, tt.userID)
+				want = []int{variant, variant, variant}
+			}
+
+			var got []int
+			for range want {
+				banner, assignment, err := s.pickBanner("1:1", entry, tt.userID, false)
+				if err != nil {
+					t.Fatalf("pickBanner: %v", err)
+				}
+				if assigned := assignment != nil; assigned != tt.wantAssigned {
+					t.Fatalf("pickBanner assignment = %+v, want assigned %v", assignment, tt.wantAssigned)
+				}
+				if assignment != nil && (assignment.ExperimentID != 7 || assignment.BannerID != banner.BannerID) {
+					t.Errorf("assignment = %+v, want experiment 7 and banner %d", assignment, banner.BannerID)
+				}
+				got = append(got, banner.BannerID)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("picked banners = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestAssignVariantSplitsTraffic(t *testing.T) {
+	tests := []struct {
+		name      string
+		traffic   []int
+		wantShare float64
+	}{
+		{"even split", []int{50, 50}, 0.5},
+		{"uneven split", []int{20, 80}, 0.2},
+		{"three variants", []int{10, 30, 60}, 0.1},
+	}
+
+	const users = 10000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			experiment := &models.Experiment{ExperimentID: 3}
+			for i, traffic := range tt.traffic {
+				experiment.Variants = append(experiment.Variants, models.ExperimentVariant{BannerID: i + 1, Traffic: traffic})
+			}
+
+			counts := make(map[int]int)
+			for i := 0; i < users; i++ {
+				userID := "user-" + strconv.Itoa(i)
+				variant := assignVariant(experiment, userID)
+				if again := assignVariant(experiment, userID); again != variant {
+					t.Fatalf("user %s assigned %d, then %d", userID, variant, again)
+				}
+				counts[variant]++
+			}
+
+			if counts[0] != 0 {
+				t.Errorf("%d users were not assigned a variant", counts[0])
+			}
+			if share := float64(counts[1]) / users; share < tt.wantShare-0.02 || share > tt.wantShare+0.02 {
+				t.Errorf("variant 1 share = %.3f, want about %.2f (counts %v)", share, tt.wantShare, counts)
+			}
+		})
+	}
+}
+
+func TestCheckBannerServed(t *testing.T) {
+	tests := []struct {
+		name     string
+		banners  []*models.Banner
+		bannerID int
+		wantErr  error
+	}{
+		{"served banner", []*models.Banner{testBanner(1, true, 1), testBanner(2, true, 1)}, 2, nil},
+		{"unknown banner", []*models.Banner{testBanner(1, true, 1)}, 99, apperrors.ErrNotFound},
+		{"inactive banner", []*models.Banner{testBanner(1, true, 1), testBanner(2, false, 1)}, 2, apperrors.ErrNotFound},
+		{"no banners for the pair", nil, 1, apperrors.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbRepo := &fakeBannerRepo{banners: tt.banners}
+			s := newTestBannerService(newFakeCacheRepo(), dbRepo, nil)
+
+			for i := 0; i < 2; i++ {
+				if err := s.CheckBannerServed(context.Background(), 1, 1, tt.bannerID); !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CheckBannerServed(%d) = %v, want %v", tt.bannerID, err, tt.wantErr)
+				}
+			}
+			if dbRepo.candidateCalls != 1 {
+				t.Errorf("database was queried %d times, want the second check served from cache", dbRepo.candidateCalls)
+			}
+		})
+	}
+}
+
+func TestGetBannerSkipsCachingBannersInvalidatedDuringLoad(t *testing.T) {
+	tests := []struct {
+		name       string
+		banners    []*models.Banner
+		invalidate func(s *BannerService)
+		wantCached bool
+	}{
+		{"no invalidation", []*models.Banner{testBanner(1, true, 1)}, func(s *BannerService) {}, true},
+		{"local invalidation", []*models.Banner{testBanner(1, true, 1)}, func(s *BannerService) {
+			s.invalidateBanners(context.Background(), testBanner(1, true, 1))
+		}, false},
+		{"invalidation from another replica", []*models.Banner{testBanner(1, true, 1)}, func(s *BannerService) {
+			s.MarkInvalidated([]string{utils.MakeCacheKey(1, 1)})
+		}, false},
+		{"not found invalidated by another replica", nil, func(s *BannerService) {
+			s.MarkInvalidated([]string{utils.MakeCacheKey(1, 1)})
+		}, false},
+		{"other key invalidated", []*models.Banner{testBanner(1, true, 1)}, func(s *BannerService) {
+			s.MarkInvalidated([]string{utils.MakeCacheKey(1, 2)})
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheRepo := newFakeCacheRepo()
+			dbRepo := &fakeBannerRepo{banners: tt.banners}
+			s := newTestBannerService(cacheRepo, dbRepo, nil)
+			dbRepo.onLoad = func() { tt.invalidate(s) }
+
+			_, _, _ = s.GetBanner(context.Background(), 1, 1, "", false, false)
+
+			if _, cached := cacheRepo.entries[utils.MakeCacheKey(1, 1)]; cached != tt.wantCached {
+				t.Errorf("entry cached = %v, want %v", cached, tt.wantCached)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package bannerservice
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/i18n"
+	"banner-service/internal/models"
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"strconv"
+)
+
+const experimentBuckets = 100
+
+type DBExperimentRepository interface {
+	GetExperiments(ctx context.Context, featureIDs []int, limit, offset int) ([]*models.Experiment, error)
+	GetExperiment(ctx context.Context, experimentID int) (*models.Experiment, error)
+	GetCurrentExperiment(ctx context.Context, featureID, tagID int) (*models.Experiment, error)
+	CreateExperiment(ctx context.Context, experiment *models.Experiment) (int, error)
+	SetExperimentStatus(ctx context.Context, experimentID int, status string, winnerBannerID int) error
+}
+
+var (
+	errExperimentStatus = apperrors.Conflict(i18n.ExperimentStatus, nil)
+	errWinnerInvalid    = apperrors.InvalidField("winner_banner_id", i18n.WinnerInvalid)
+	errRotationRequired = apperrors.Conflict(i18n.RotationRequired, nil)
+)
+
+type ExperimentService struct {
+	experimentRepo DBExperimentRepository
+	featureRepo    DBFeatureRepository
+	bannerService  *BannerService
+	logger         *slog.Logger
+}
+
+func NewExperimentService(experimentRepo DBExperimentRepository, featureRepo DBFeatureRepository, bannerService *BannerService, logger *slog.Logger) *ExperimentService {
+	return &ExperimentService{
+		experimentRepo: experimentRepo,
+		featureRepo:    featureRepo,
+		bannerService:  bannerService,
+		logger:         logger,
+	}
+}
+
+func (s *ExperimentService) GetExperiments(ctx context.Context, featureIDs []int, limit, offset int) ([]*models.Experiment, error) {
+	if limit <= 0 {
+		limit = 0
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return s.experimentRepo.GetExperiments(ctx, featureIDs, limit, offset)
+}
+
+func (s *ExperimentService) GetExperiment(ctx context.Context, experimentID int) (*models.Experiment, error) {
+	return s.experimentRepo.GetExperiment(ctx, experimentID)
+}
+
+func (s *ExperimentService) CreateExperiment(ctx context.Context, experiment *models.Experiment, author string) (int, error) {
+	if err := validateExperiment(experiment); err != nil {
+		return 0, err
+	}
+
+	feature, err := s.featureRepo.GetFeature(ctx, experiment.FeatureID)
+	if err != nil {
+		return 0, err
+	}
+	if feature.RotationMode == models.RotationModeNone {
+		return 0, errRotationRequired
+	}
+
+	experimentID, err := s.experimentRepo.CreateExperiment(ctx, experiment)
+	if err != nil {
+		return 0, err
+	}
+
+	s.invalidate(ctx, experiment)
+	s.logger.InfoContext(ctx, "experiment created", "experiment_id", experimentID, "feature_id", experiment.FeatureID, "tag_id", experiment.TagID, "author", author)
+
+	return experimentID, nil
+}
+
+func (s *ExperimentService) PauseExperiment(ctx context.Context, experimentID int, author string) error {
+	return s.setStatus(ctx, experimentID, models.ExperimentStatusPaused, 0, author, models.ExperimentStatusRunning)
+}
+
+func (s *ExperimentService) ResumeExperiment(ctx context.Context, experimentID int, author string) error {
+	return s.setStatus(ctx, experimentID, models.ExperimentStatusRunning, 0, author, models.ExperimentStatusPaused)
+}
+
+func (s *ExperimentService) ConcludeExperiment(ctx context.Context, experimentID, winnerBannerID int, author string) error {
+	return s.setStatus(ctx, experimentID, models.ExperimentStatusConcluded, winnerBannerID, author, models.ExperimentStatusRunning, models.ExperimentStatusPaused)
+}
+
+func (s *ExperimentService) setStatus(ctx context.Context, experimentID int, status string, winnerBannerID int, author string, from ...string) error {
+	experiment, err := s.experimentRepo.GetExperiment(ctx, experimentID)
+	if err != nil {
+		return err
+	}
+
+	allowed := false
+	for _, current := range from {
+		allowed = allowed || experiment.Status == current
+	}
+	if !allowed {
+		return errExperimentStatus
+	}
+
+	if winnerBannerID != 0 {
+		valid := false
+		for _, variant := range experiment.Variants {
+			valid = valid || variant.BannerID == winnerBannerID
+		}
+		if !valid {
+			return errWinnerInvalid
+		}
+	}
+
+	if err := s.experimentRepo.SetExperimentStatus(ctx, experimentID, status, winnerBannerID); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx, experiment)
+	s.logger.InfoContext(ctx, "experiment status changed", "experiment_id", experimentID, "from", experiment.Status, "to", status, "author", author)
+
+	return nil
+}
+
+func (s *ExperimentService) invalidate(ctx context.Context, experiment *models.Experiment) {
+	s.bannerService.invalidateBanners(ctx, &models.Banner{FeatureID: experiment.FeatureID, TagIDs: []int{experiment.TagID}})
+}
+
+func validateExperiment(experiment *models.Experiment) error {
+	if experiment == nil {
+		return apperrors.Validation(i18n.InvalidRequest)
+	}
+
+	var details []apperrors.FieldError
+	if name, err := validateName(experiment.Name); err != nil {
+		details = append(details, apperrors.FieldError{Field: "name", Message: i18n.NameInvalid})
+	} else {
+		experiment.Name = name
+	}
+	if experiment.FeatureID <= 0 {
+		details = append(details, apperrors.FieldError{Field: "feature_id", Message: i18n.FeatureIDInvalid})
+	}
+	if experiment.TagID <= 0 {
+		details = append(details, apperrors.FieldError{Field: "tag_id", Message: i18n.FieldPositiveInt})
+	}
+
+	total := 0
+	seen := make(map[int]struct{}, len(experiment.Variants))
+	for _, variant := range experiment.Variants {
+		if _, ok := seen[variant.BannerID]; ok || variant.BannerID <= 0 || variant.Traffic <= 0 {
+			total = -1
+			break
+		}
+		seen[variant.BannerID] = struct{}{}
+		total += variant.Traffic
+	}
+	if len(experiment.Variants) < 2 || total != experimentBuckets {
+		details = append(details, apperrors.FieldError{Field: "variants", Message: i18n.VariantsInvalid})
+	}
+
+	if len(details) > 0 {
+		return apperrors.Validation(i18n.InvalidRequest, details...)
+	}
+
+	return nil
+}
+
+func assignVariant(experiment *models.Experiment, userID string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strconv.Itoa(experiment.ExperimentID) + ":" + userID))
+
+	bucket := int(h.Sum32() % experimentBuckets)
+	for _, variant := range experiment.Variants {
+		if bucket -= variant.Traffic; bucket < 0 {
+			return variant.BannerID
+		}
+	}
+
+	return 0
+}
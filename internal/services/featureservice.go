@@ -0,0 +1,155 @@
+package bannerservice
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/i18n"
+	"banner-service/internal/models"
+	"context"
+	"log/slog"
+	"strings"
+)
+
+const maxNameLength = 255
+
+var (
+	ErrInvalidCachePolicy  = apperrors.InvalidField("cache_policy", i18n.CachePolicyInvalid)
+	ErrInvalidRotationMode = apperrors.InvalidField("rotation_mode", i18n.RotationModeInvalid)
+)
+
+type FeatureService struct {
+	featureRepo   DBFeatureRepository
+	bannerRepo    DBBannerRepository
+	bannerService *BannerService
+	logger        *slog.Logger
+}
+
+func NewFeatureService(featureRepo DBFeatureRepository, bannerRepo DBBannerRepository, bannerService *BannerService, logger *slog.Logger) *FeatureService {
+	return &FeatureService{
+		featureRepo:   featureRepo,
+		bannerRepo:    bannerRepo,
+		bannerService: bannerService,
+		logger:        logger,
+	}
+}
+
+func (s *FeatureService) GetFeatures(ctx context.Context, limit, offset int) ([]*models.Feature, error) {
+	if limit <= 0 {
+		limit = 0
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return s.featureRepo.GetFeatures(ctx, limit, offset)
+}
+
+func (s *FeatureService) GetFeature(ctx context.Context, featureID int) (*models.Feature, error) {
+	return s.featureRepo.GetFeature(ctx, featureID)
+}
+
+func (s *FeatureService) CreateFeature(ctx context.Context, feature *models.Feature) (int, error) {
+	if feature == nil {
+		return 0, apperrors.Validation(i18n.InvalidRequest)
+	}
+	name, err := validateName(feature.Name)
+	if err != nil {
+		return 0, err
+	}
+	feature.Name = name
+
+	return s.featureRepo.CreateFeature(ctx, feature)
+}
+
+func (s *FeatureService) UpdateFeature(ctx context.Context, featureID int, feature *models.Feature) error {
+	if feature == nil {
+		return apperrors.Validation(i18n.InvalidRequest)
+	}
+	name, err := validateName(feature.Name)
+	if err != nil {
+		return err
+	}
+	feature.Name = name
+
+	return s.featureRepo.UpdateFeature(ctx, featureID, feature)
+}
+
+func (s *FeatureService) SetFeatureCachePolicy(ctx context.Context, featureID int, policy string, ttlSeconds int) error {
+	switch policy {
+	case models.CachePolicyTTL:
+		if ttlSeconds <= 0 {
+			return ErrInvalidCachePolicy
+		}
+	case models.CachePolicyDefault, models.CachePolicyNever, models.CachePolicyForever:
+		if ttlSeconds != 0 {
+			return ErrInvalidCachePolicy
+		}
+	default:
+		return ErrInvalidCachePolicy
+	}
+
+	if err := s.featureRepo.SetFeatureCachePolicy(ctx, featureID, policy, ttlSeconds); err != nil {
+		return err
+	}
+
+	banners, err := s.bannerRepo.GetBanners(ctx, models.BannerFilter{FeatureID: featureID})
+	if err != nil {
+		return err
+	}
+
+	s.bannerService.invalidateBanners(ctx, banners...)
+
+	return nil
+}
+
+func (s *FeatureService) SetFeatureRotationMode(ctx context.Context, featureID int, mode string) error {
+	switch mode {
+	case models.RotationModeNone, models.RotationModeWeighted, models.RotationModeRoundRobin:
+	default:
+		return ErrInvalidRotationMode
+	}
+
+	if err := s.featureRepo.SetFeatureRotationMode(ctx, featureID, mode); err != nil {
+		return err
+	}
+
+	banners, err := s.bannerRepo.GetBanners(ctx, models.BannerFilter{FeatureID: featureID})
+	if err != nil {
+		return err
+	}
+
+	s.bannerService.invalidateBanners(ctx, banners...)
+
+	return nil
+}
+
+func (s *FeatureService) DeleteFeature(ctx context.Context, featureID int, dryRun bool, author string) (*models.DeletePreview, error) {
+	preview, err := s.featureRepo.PreviewFeatureDeletion(ctx, featureID)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return preview, nil
+	}
+
+	banners, err := s.bannerRepo.GetBanners(ctx, models.BannerFilter{FeatureID: featureID})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.featureRepo.DeleteFeature(ctx, featureID, author); err != nil {
+		return nil, err
+	}
+
+	s.bannerService.invalidateBanners(ctx, banners...)
+
+	return preview, nil
+}
+
+func validateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxNameLength {
+		return "", apperrors.InvalidField("name", i18n.NameInvalid)
+	}
+
+	return name, nil
+}
@@ -0,0 +1,86 @@
+package bannerservice
+
+import (
+	"banner-service/internal/models"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type HealthCheck struct {
+	Name     string
+	Critical bool
+	Ping     func(ctx context.Context) error
+}
+
+type HealthService struct {
+	timeout time.Duration
+	checks  []HealthCheck
+	ready   atomic.Bool
+}
+
+func NewHealthService(timeout time.Duration, checks ...HealthCheck) *HealthService {
+	return &HealthService{
+		timeout: timeout,
+		checks:  checks,
+	}
+}
+
+func (s *HealthService) SetReady(ready bool) {
+	s.ready.Store(ready)
+}
+
+func (s *HealthService) Ready() bool {
+	return s.ready.Load()
+}
+
+func (s *HealthService) Check(ctx context.Context) *models.HealthReport {
+	if !s.Ready() {
+		return &models.HealthReport{Status: models.HealthStatusNotReady}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	results := make([]models.DependencyHealth, len(s.checks))
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := check.Ping(ctx)
+			results[i] = models.DependencyHealth{
+				Status:    models.DependencyStatusUp,
+				Critical:  check.Critical,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				results[i].Status = models.DependencyStatusDown
+				results[i].Error = err.Error()
+			}
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := &models.HealthReport{
+		Status:       models.HealthStatusOK,
+		Dependencies: make(map[string]models.DependencyHealth, len(s.checks)),
+	}
+	for i, check := range s.checks {
+		result := results[i]
+		report.Dependencies[check.Name] = result
+		if result.Status == models.DependencyStatusUp {
+			continue
+		}
+		if check.Critical {
+			report.Status = models.HealthStatusUnavailable
+		} else if report.Status == models.HealthStatusOK {
+			report.Status = models.HealthStatusDegraded
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,166 @@
+package bannerservice
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/i18n"
+	"banner-service/internal/logging"
+	"banner-service/internal/models"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	deletionBatchSize = 100
+	jobPollInterval   = 5 * time.Second
+	jobStaleAfter     = time.Minute
+)
+
+type DBJobRepository interface {
+	CreateJob(ctx context.Context, job *models.Job) (int, error)
+	GetJob(ctx context.Context, jobID int) (*models.Job, error)
+	ClaimJob(ctx context.Context, staleAfter time.Duration) (*models.Job, error)
+	UpdateJobProgress(ctx context.Context, jobID, total, processed int) error
+	FinishJob(ctx context.Context, jobID int, status, errMsg string) error
+}
+
+var ErrDeletionFilterRequired = apperrors.Validation(i18n.InvalidRequest,
+	apperrors.FieldError{Field: "feature_id", Message: i18n.FeatureOrTagRequired},
+	apperrors.FieldError{Field: "tag_id", Message: i18n.FeatureOrTagRequired},
+)
+
+type JobService struct {
+	jobRepo       DBJobRepository
+	bannerRepo    DBBannerRepository
+	bannerService *BannerService
+	warmup        *WarmupService
+	wakeup        chan struct{}
+	logger        *slog.Logger
+}
+
+func NewJobService(jobRepo DBJobRepository, bannerRepo DBBannerRepository, bannerService *BannerService, warmup *WarmupService, logger *slog.Logger) *JobService {
+	return &JobService{
+		jobRepo:       jobRepo,
+		bannerRepo:    bannerRepo,
+		bannerService: bannerService,
+		warmup:        warmup,
+		wakeup:        make(chan struct{}, 1),
+		logger:        logger,
+	}
+}
+
+func (s *JobService) ScheduleBannersDeletion(ctx context.Context, featureID, tagID int, author string) (int, error) {
+	if featureID <= 0 && tagID <= 0 {
+		return 0, ErrDeletionFilterRequired
+	}
+
+	jobID, err := s.jobRepo.CreateJob(ctx, &models.Job{
+		Kind:      models.JobKindDeleteBanners,
+		FeatureID: featureID,
+		TagID:     tagID,
+		Author:    author,
+		RequestID: logging.RequestID(ctx),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+
+	return jobID, nil
+}
+
+func (s *JobService) GetJob(ctx context.Context, jobID int) (*models.Job, error) {
+	return s.jobRepo.GetJob(ctx, jobID)
+}
+
+func (s *JobService) Run(ctx context.Context) {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for s.processNextJob(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wakeup:
+		}
+	}
+}
+
+func (s *JobService) processNextJob(ctx context.Context) bool {
+	job, err := s.jobRepo.ClaimJob(ctx, jobStaleAfter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "could not claim job", "error", err)
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	requestID := job.RequestID
+	if requestID == "" {
+		requestID = fmt.Sprintf("job-%d", job.JobID)
+	}
+
+	status, errMsg := models.JobStatusDone, ""
+	if err := s.deleteBanners(logging.WithRequestID(ctx, requestID), job); err != nil {
+		if ctx.Err() != nil {
+			return false
+		}
+		s.logger.ErrorContext(ctx, "job failed", "job_id", job.JobID, "kind", job.Kind, "error", err)
+		status, errMsg = models.JobStatusFailed, err.Error()
+	}
+
+	if err := s.jobRepo.FinishJob(ctx, job.JobID, status, errMsg); err != nil {
+		s.logger.ErrorContext(ctx, "could not finish job", "job_id", job.JobID, "error", err)
+	}
+
+	if s.warmup != nil && status == models.JobStatusDone {
+		s.warmup.Start(ctx)
+	}
+
+	return true
+}
+
+func (s *JobService) deleteBanners(ctx context.Context, job *models.Job) error {
+	filter := models.BannerFilter{FeatureID: job.FeatureID, TagID: job.TagID}
+
+	remaining, err := s.bannerRepo.CountBanners(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	total := job.Processed + remaining
+	processed := job.Processed
+	if err := s.jobRepo.UpdateJobProgress(ctx, job.JobID, total, processed); err != nil {
+		return err
+	}
+
+	for {
+		deleted, err := s.bannerRepo.DeleteBannersBatch(ctx, filter, deletionBatchSize, job.Author)
+		if err != nil {
+			return err
+		}
+		if len(deleted) == 0 {
+			return nil
+		}
+
+		s.bannerService.invalidateBanners(ctx, deleted...)
+
+		processed += len(deleted)
+		if processed > total {
+			total = processed
+		}
+		if err := s.jobRepo.UpdateJobProgress(ctx, job.JobID, total, processed); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package bannerservice
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/config"
+	"banner-service/internal/i18n"
+	"banner-service/internal/metrics"
+	"banner-service/internal/models"
+	"banner-service/internal/ratelimit"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	statsFlushTimeout = 5 * time.Second
+	statsHourlyWindow = 24 * time.Hour
+	statsDailyWindow  = 30 * 24 * time.Hour
+)
+
+type DBStatsRepository interface {
+	AddBannerStats(ctx context.Context, deltas []*models.BannerStatsDelta) error
+	GetBannerStats(ctx context.Context, bannerID int, from, to time.Time, granularity string) ([]models.BannerStatsPoint, error)
+}
+
+var (
+	errGranularityInvalid = apperrors.InvalidField("granularity", i18n.GranularityInvalid)
+	errStatsRangeInvalid  = apperrors.InvalidField("from", i18n.StatsRangeInvalid)
+	errClickRateLimited   = apperrors.RateLimited(i18n.TooManyRequests)
+)
+
+type statsKey struct {
+	bannerID int
+	bucket   time.Time
+}
+
+type StatsService struct {
+	statsRepo DBStatsRepository
+	cfg       config.StatsConfig
+	mu        sync.Mutex
+	buffer    map[statsKey]*models.BannerStatsDelta
+	clicks    *ratelimit.Limiter
+	wakeup    chan struct{}
+	logger    *slog.Logger
+}
+
+func NewStatsService(statsRepo DBStatsRepository, cfg config.StatsConfig, logger *slog.Logger) *StatsService {
+	return &StatsService{
+		statsRepo: statsRepo,
+		cfg:       cfg,
+		buffer:    make(map[statsKey]*models.BannerStatsDelta),
+		clicks:    ratelimit.New(cfg.ClicksPerMinute, time.Minute),
+		wakeup:    make(chan struct{}, 1),
+		logger:    logger,
+	}
+}
+
+func (s *StatsService) RecordImpression(bannerID int) {
+	s.record(&models.BannerStatsDelta{BannerID: bannerID, Bucket: time.Now().UTC().Truncate(time.Hour), Impressions: 1})
+}
+
+func (s *StatsService) AllowClick(userID string) error {
+	if !s.clicks.Allow(userID) {
+		return errClickRateLimited
+	}
+
+	return nil
+}
+
+func (s *StatsService) RecordClick(bannerID int) {
+	s.record(&models.BannerStatsDelta{BannerID: bannerID, Bucket: time.Now().UTC().Truncate(time.Hour), Clicks: 1})
+}
+
+func (s *StatsService) GetBannerStats(ctx context.Context, bannerID int, from, to time.Time, granularity string) (*models.BannerStats, error) {
+	window := statsHourlyWindow
+	switch granularity {
+	case "", models.StatsGranularityHour:
+		granularity = models.StatsGranularityHour
+	case models.StatsGranularityDay:
+		window = statsDailyWindow
+	default:
+		return nil, errGranularityInvalid
+	}
+
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-window)
+	}
+	if !from.Before(to) {
+		return nil, errStatsRangeInvalid
+	}
+
+	points, err := s.statsRepo.GetBannerStats(ctx, bannerID, from, to, granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.BannerStats{
+		BannerID:    bannerID,
+		Granularity: granularity,
+		From:        from,
+		To:          to,
+		Points:      points,
+	}
+	for _, point := range points {
+		stats.Impressions += point.Impressions
+		stats.Clicks += point.Clicks
+	}
+
+	return stats, nil
+}
+
+func (s *StatsService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), statsFlushTimeout)
+			s.flush(flushCtx)
+			cancel()
+			return
+		case <-ticker.C:
+		case <-s.wakeup:
+		}
+
+		s.flush(ctx)
+	}
+}
+
+func (s *StatsService) record(delta *models.BannerStatsDelta) {
+	s.mu.Lock()
+	buffered := len(s.buffer)
+	kept := s.merge(delta)
+	full := buffered < s.cfg.BufferSize && len(s.buffer) >= s.cfg.BufferSize
+	s.mu.Unlock()
+
+	if !kept {
+		metrics.ObserveDroppedStats(1)
+	}
+	if full {
+		select {
+		case s.wakeup <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *StatsService) merge(delta *models.BannerStatsDelta) bool {
+	key := statsKey{bannerID: delta.BannerID, bucket: delta.Bucket}
+	if buffered, ok := s.buffer[key]; ok {
+		buffered.Impressions += delta.Impressions
+		buffered.Clicks += delta.Clicks
+		return true
+	}
+	if len(s.buffer) >= s.cfg.BufferSize {
+		return false
+	}
+	s.buffer[key] = delta
+
+	return true
+}
+
+func (s *StatsService) flush(ctx context.Context) {
+	s.mu.Lock()
+	buffer := s.buffer
+	s.buffer = make(map[statsKey]*models.BannerStatsDelta, len(buffer))
+	s.mu.Unlock()
+
+	if len(buffer) == 0 {
+		return
+	}
+
+	deltas := make([]*models.BannerStatsDelta, 0, len(buffer))
+	for _, delta := range buffer {
+		deltas = append(deltas, delta)
+	}
+
+	if err := s.statsRepo.AddBannerStats(ctx, deltas); err != nil {
+		dropped := 0
+		s.mu.Lock()
+		for _, delta := range deltas {
+			if !s.merge(delta) {
+				dropped++
+			}
+		}
+		s.mu.Unlock()
+
+		metrics.ObserveDroppedStats(dropped)
+		s.logger.ErrorContext(ctx, "could not flush banner stats, keeping them for the next attempt", "deltas", len(deltas), "dropped", dropped, "error", err)
+		return
+	}
+
+	s.logger.DebugContext(ctx, "flushed banner stats", "deltas", len(deltas))
+}
@@ -0,0 +1,161 @@
+package bannerservice
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/config"
+	"banner-service/internal/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeStatsRepo struct {
+	DBStatsRepository
+
+	err     error
+	flushes [][]*models.BannerStatsDelta
+}
+
+func (r *fakeStatsRepo) AddBannerStats(ctx context.Context, deltas []*models.BannerStatsDelta) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.flushes = append(r.flushes, deltas)
+
+	return nil
+}
+
+func statsTotals(deltas []*models.BannerStatsDelta) map[int][2]int64 {
+	totals := make(map[int][2]int64)
+	for _, delta := range deltas {
+		total := totals[delta.BannerID]
+		totals[delta.BannerID] = [2]int64{total[0] + delta.Impressions, total[1] + delta.Clicks}
+	}
+
+	return totals
+}
+
+func TestStatsServiceMergesBufferedEvents(t *testing.T) {
+	repo := &fakeStatsRepo{}
+	s := NewStatsService(repo, config.StatsConfig{FlushInterval: time.Minute, BufferSize: 100}, testLogger)
+
+	for i := 0; i < 3; i++ {
+		s.RecordImpression(1)
+	}
+	s.RecordClick(1)
+	s.RecordImpression(2)
+
+	s.flush(context.Background())
+
+	if len(repo.flushes) != 1 {
+		t.Fatalf("flushed %d times, want 1", len(repo.flushes))
+	}
+	if n := len(repo.flushes[0]); n != 2 {
+		t.Errorf("flushed %d deltas, want one per banner and hour", n)
+	}
+	totals := statsTotals(repo.flushes[0])
+	if totals[1] != [2]int64{3, 1} || totals[2] != [2]int64{1, 0} {
+		t.Errorf("flushed totals = %v, want banner 1: 3/1, banner 2: 1/0", totals)
+	}
+
+	s.flush(context.Background())
+	if len(repo.flushes) != 1 {
+		t.Errorf("an empty buffer was flushed")
+	}
+}
+
+func TestStatsServiceKeepsEventsAfterFailedFlush(t *testing.T) {
+	repo := &fakeStatsRepo{err: errors.New("database is down")}
+	s := NewStatsService(repo, config.StatsConfig{FlushInterval: time.Minute, BufferSize: 100}, testLogger)
+
+	s.RecordImpression(1)
+	s.RecordClick(1)
+	s.flush(context.Background())
+
+	s.RecordImpression(1)
+	repo.err = nil
+	s.flush(context.Background())
+
+	if len(repo.flushes) != 1 {
+		t.Fatalf("flushed %d times, want 1", len(repo.flushes))
+	}
+	if totals := statsTotals(repo.flushes[0]); totals[1] != [2]int64{2, 1} {
+		t.Errorf("flushed totals = %v, want banner 1: 2/1", totals)
+	}
+}
+
+func TestStatsServiceWakesUpWhenBufferIsFull(t *testing.T) {
+	s := NewStatsService(&fakeStatsRepo{}, config.StatsConfig{FlushInterval: time.Minute, BufferSize: 2}, testLogger)
+
+	s.RecordImpression(1)
+	select {
+	case <-s.wakeup:
+		t.Fatal("flush was requested before the buffer was full")
+	default:
+	}
+
+	s.RecordImpression(2)
+	select {
+	case <-s.wakeup:
+	default:
+		t.Fatal("flush was not requested when the buffer filled up")
+	}
+}
+
+func TestGetBannerStatsValidatesQuery(t *testing.T) {
+	s := NewStatsService(&fakeStatsRepo{}, config.StatsConfig{FlushInterval: time.Minute, BufferSize: 100}, testLogger)
+	now := time.Now()
+
+	if _, err := s.GetBannerStats(context.Background(), 1, time.Time{}, time.Time{}, "week"); !errors.Is(err, errGranularityInvalid) {
+		t.Errorf("GetBannerStats(week) = %v, want errGranularityInvalid", err)
+	}
+	if _, err := s.GetBannerStats(context.Background(), 1, now, now.Add(-time.Hour), ""); !errors.Is(err, errStatsRangeInvalid) {
+		t.Errorf("GetBannerStats(from after to) = %v, want errStatsRangeInvalid", err)
+	}
+}
+
+func TestStatsServiceThrottlesClicksPerUser(t *testing.T) {
+	s := NewStatsService(&fakeStatsRepo{}, config.StatsConfig{FlushInterval: time.Minute, BufferSize: 100, ClicksPerMinute: 2}, testLogger)
+
+	for i, userID := range []string{"alice", "alice", "bob"} {
+		if err := s.AllowClick(userID); err != nil {
+			t.Fatalf("AllowClick(%s) #%d = %v, want allowed", userID, i, err)
+		}
+	}
+	if err := s.AllowClick("alice"); !errors.Is(err, apperrors.ErrRateLimited) {
+		t.Errorf("third AllowClick(alice) = %v, want ErrRateLimited", err)
+	}
+}
+
+func TestStatsServiceCapsBufferWhileFlushesFail(t *testing.T) {
+	repo := &fakeStatsRepo{err: errors.New("database is down")}
+	s := NewStatsService(repo, config.StatsConfig{FlushInterval: time.Minute, BufferSize: 3}, testLogger)
+
+	for bannerID := 1; bannerID <= 3; bannerID++ {
+		s.RecordImpression(bannerID)
+	}
+	<-s.wakeup
+	s.flush(context.Background())
+
+	for bannerID := 4; bannerID <= 10; bannerID++ {
+		s.RecordImpression(bannerID)
+	}
+	s.RecordClick(1)
+	s.flush(context.Background())
+
+	if n := len(s.buffer); n != 3 {
+		t.Fatalf("buffer holds %d deltas, want it capped at 3", n)
+	}
+	select {
+	case <-s.wakeup:
+		t.Error("a full buffer kept requesting flushes")
+	default:
+	}
+
+	repo.err = nil
+	s.flush(context.Background())
+	if totals := statsTotals(repo.flushes[0]); totals[1] != [2]int64{1, 1} || len(totals) != 3 {
+		t.Errorf("flushed totals = %v, want banners 1-3 with the click on banner 1", totals)
+	}
+}
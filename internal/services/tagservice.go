@@ -0,0 +1,89 @@
+package bannerservice
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/i18n"
+	"banner-service/internal/models"
+	"context"
+	"log/slog"
+)
+
+type TagService struct {
+	tagRepo       DBTagRepository
+	bannerRepo    DBBannerRepository
+	bannerService *BannerService
+	logger        *slog.Logger
+}
+
+func NewTagService(tagRepo DBTagRepository, bannerRepo DBBannerRepository, bannerService *BannerService, logger *slog.Logger) *TagService {
+	return &TagService{
+		tagRepo:       tagRepo,
+		bannerRepo:    bannerRepo,
+		bannerService: bannerService,
+		logger:        logger,
+	}
+}
+
+func (s *TagService) GetTags(ctx context.Context, limit, offset int) ([]*models.Tag, error) {
+	if limit <= 0 {
+		limit = 0
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return s.tagRepo.GetTags(ctx, limit, offset)
+}
+
+func (s *TagService) GetTag(ctx context.Context, tagID int) (*models.Tag, error) {
+	return s.tagRepo.GetTag(ctx, tagID)
+}
+
+func (s *TagService) CreateTag(ctx context.Context, tag *models.Tag) (int, error) {
+	if tag == nil {
+		return 0, apperrors.Validation(i18n.InvalidRequest)
+	}
+	name, err := validateName(tag.Name)
+	if err != nil {
+		return 0, err
+	}
+	tag.Name = name
+
+	return s.tagRepo.CreateTag(ctx, tag)
+}
+
+func (s *TagService) UpdateTag(ctx context.Context, tagID int, tag *models.Tag) error {
+	if tag == nil {
+		return apperrors.Validation(i18n.InvalidRequest)
+	}
+	name, err := validateName(tag.Name)
+	if err != nil {
+		return err
+	}
+	tag.Name = name
+
+	return s.tagRepo.UpdateTag(ctx, tagID, tag)
+}
+
+func (s *TagService) DeleteTag(ctx context.Context, tagID int, dryRun bool, author string) (*models.DeletePreview, error) {
+	preview, err := s.tagRepo.PreviewTagDeletion(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return preview, nil
+	}
+
+	banners, err := s.bannerRepo.GetBanners(ctx, models.BannerFilter{TagID: tagID})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tagRepo.DeleteTag(ctx, tagID, author); err != nil {
+		return nil, err
+	}
+
+	s.bannerService.invalidateBanners(ctx, banners...)
+
+	return preview, nil
+}
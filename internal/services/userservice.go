@@ -0,0 +1,235 @@
+package bannerservice
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/auth"
+	"banner-service/internal/config"
+	"banner-service/internal/i18n"
+	"banner-service/internal/models"
+	"banner-service/internal/ratelimit"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	userTokenTTL   = 15 * time.Minute
+	apiTokenLength = 32
+	minPasswordLen = 8
+	maxPasswordLen = 72
+
+	dummyPasswordHash = "$2a$10$5uoqgAYVy9FgXmJTSkxJ8uobrW1SPBImdO6CLDeVgHLEEpDLwlPlC"
+)
+
+var (
+	ErrInvalidCredentials = apperrors.Unauthorized(i18n.InvalidCredentials)
+	ErrTokensDisabled     = apperrors.Forbidden(i18n.TokensDisabled)
+	errLoginRateLimited   = apperrors.RateLimited(i18n.TooManyRequests)
+)
+
+type DBUserRepository interface {
+	GetUserByID(ctx context.Context, userID int) (*models.User, error)
+	GetUserByLogin(ctx context.Context, login string) (*models.User, error)
+	GetUserByToken(ctx context.Context, tokenHash string) (*models.User, error)
+	CreateUser(ctx context.Context, user *models.User, tokenHash string) (int, error)
+	UpsertAdmin(ctx context.Context, user *models.User) error
+	SetUserRole(ctx context.Context, userID int, role string, isAdmin bool, featureIDs []int) error
+	SetUserToken(ctx context.Context, userID int, tokenHash string) error
+}
+
+type UserService struct {
+	userRepo      DBUserRepository
+	signer        *auth.Signer
+	loginAttempts *ratelimit.Limiter
+	ipAttempts    *ratelimit.Limiter
+}
+
+func NewUserService(userRepo DBUserRepository, signer *auth.Signer, cfg config.AuthConfig) *UserService {
+	return &UserService{
+		userRepo:      userRepo,
+		signer:        signer,
+		loginAttempts: ratelimit.New(cfg.LoginAttemptsPerMinute, time.Minute),
+		ipAttempts:    ratelimit.New(cfg.LoginIPAttemptsPerMinute, time.Minute),
+	}
+}
+
+func (s *UserService) Login(ctx context.Context, login, password, clientIP string) (string, error) {
+	if s.signer == nil {
+		return "", ErrTokensDisabled
+	}
+
+	if !s.ipAttempts.Allow(clientIP) || !s.loginAttempts.Allow(login) {
+		return "", errLoginRateLimited
+	}
+
+	user, err := s.userRepo.GetUserByLogin(ctx, login)
+	if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		return "", err
+	}
+
+	found := user != nil && user.PasswordHash != ""
+	passwordHash := dummyPasswordHash
+	if found {
+		passwordHash = user.PasswordHash
+	}
+	if bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password)) != nil || !found {
+		return "", ErrInvalidCredentials
+	}
+
+	principal := PrincipalForUser(user)
+	featureIDs := principal.FeatureIDs
+	if featureIDs == nil {
+		featureIDs = []int{}
+	}
+
+	return s.signer.Sign(jwt.MapClaims{
+		"sub":         principal.UserID,
+		"user_id":     user.UserID,
+		"admin":       principal.IsAdmin(),
+		"role":        string(principal.Role),
+		"feature_ids": featureIDs,
+	}, userTokenTTL)
+}
+
+func (s *UserService) GetUser(ctx context.Context, userID int) (*models.User, error) {
+	return s.userRepo.GetUserByID(ctx, userID)
+}
+
+func (s *UserService) AuthenticateToken(ctx context.Context, token string) (*models.User, error) {
+	if token == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	user, err := s.userRepo.GetUserByToken(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *UserService) CreateUser(ctx context.Context, user *models.User, password string) (int, string, error) {
+	if user == nil {
+		return 0, "", apperrors.Validation(i18n.InvalidRequest)
+	}
+	user.Login = strings.TrimSpace(user.Login)
+	if user.Login == "" || len(user.Login) > maxNameLength {
+		return 0, "", apperrors.InvalidField("login", i18n.LoginInvalid)
+	}
+	if len(password) < minPasswordLen {
+		return 0, "", apperrors.InvalidField("password", i18n.PasswordTooShort)
+	}
+	if len(password) > maxPasswordLen {
+		return 0, "", apperrors.InvalidField("password", i18n.PasswordTooLong)
+	}
+	role, ok := auth.ParseRole(user.Role)
+	if !ok {
+		return 0, "", apperrors.InvalidField("role", i18n.RoleInvalid)
+	}
+	if user.IsAdmin {
+		role = auth.RoleAdmin
+	}
+	user.Role = string(role)
+	user.IsAdmin = role == auth.RoleAdmin
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return 0, "", err
+	}
+	user.PasswordHash = string(passwordHash)
+
+	token, err := generateToken()
+	if err != nil {
+		return 0, "", err
+	}
+
+	userID, err := s.userRepo.CreateUser(ctx, user, hashToken(token))
+	if err != nil {
+		return 0, "", err
+	}
+
+	return userID, token, nil
+}
+
+func (s *UserService) EnsureAdmin(ctx context.Context, login, password string) error {
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return s.userRepo.UpsertAdmin(ctx, &models.User{
+		Login:        login,
+		PasswordHash: string(passwordHash),
+		IsAdmin:      true,
+		Role:         string(auth.RoleAdmin),
+	})
+}
+
+func (s *UserService) SetUserRole(ctx context.Context, userID int, roleName string, featureIDs []int) error {
+	role, ok := auth.ParseRole(roleName)
+	if !ok {
+		return apperrors.InvalidField("role", i18n.RoleInvalid)
+	}
+	for _, featureID := range featureIDs {
+		if featureID <= 0 {
+			return apperrors.InvalidField("feature_ids", i18n.FeatureIDInvalid)
+		}
+	}
+
+	return s.userRepo.SetUserRole(ctx, userID, string(role), role == auth.RoleAdmin, featureIDs)
+}
+
+func (s *UserService) IssueToken(ctx context.Context, userID int) (string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.userRepo.SetUserToken(ctx, userID, hashToken(token)); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (s *UserService) RevokeToken(ctx context.Context, userID int) error {
+	return s.userRepo.SetUserToken(ctx, userID, "")
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, apiTokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func PrincipalForUser(user *models.User) *auth.Principal {
+	role, _ := auth.ParseRole(user.Role)
+	if user.IsAdmin {
+		role = auth.RoleAdmin
+	}
+
+	return &auth.Principal{
+		UserID:     strconv.Itoa(user.UserID),
+		Role:       role,
+		FeatureIDs: user.FeatureIDs,
+	}
+}
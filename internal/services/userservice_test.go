@@ -0,0 +1,124 @@
+package bannerservice
+
+import (
+	"banner-service/internal/apperrors"
+	"banner-service/internal/auth"
+	"banner-service/internal/config"
+	"banner-service/internal/models"
+	"context"
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type fakeUserRepo struct {
+	DBUserRepository
+
+	users map[string]*models.User
+}
+
+func (r *fakeUserRepo) GetUserByLogin(ctx context.Context, login string) (*models.User, error) {
+	user, ok := r.users[login]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+
+	return user, nil
+}
+
+func newTestUserService(t *testing.T, cfg config.AuthConfig) (*UserService, *auth.Verifier) {
+	t.Helper()
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	repo := &fakeUserRepo{users: map[string]*models.User{
+		"editor":   {UserID: 7, Login: "editor", PasswordHash: string(passwordHash), Role: string(auth.RoleEditor), FeatureIDs: []int{1}},
+		"external": {UserID: 8, Login: "external"},
+	}}
+
+	cfg.HMACSecret = "secret"
+	signer, err := auth.NewSigner(cfg)
+	if err != nil {
+		t.Fatalf("NewSigner: %v", err)
+	}
+	verifier, err := auth.NewVerifier(cfg)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+
+	return NewUserService(repo, signer, cfg), verifier
+}
+
+func TestUserServiceLogin(t *testing.T) {
+	s, verifier := newTestUserService(t, config.AuthConfig{LoginAttemptsPerMinute: 10, LoginIPAttemptsPerMinute: 10})
+
+	tests := []struct {
+		name     string
+		login    string
+		password string
+		wantErr  error
+	}{
+		{"valid credentials", "editor", "password", nil},
+		{"wrong password", "editor", "wrong", ErrInvalidCredentials},
+		{"unknown login", "nobody", "password", ErrInvalidCredentials},
+		{"user without password", "external", "password", ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := s.Login(context.Background(), tt.login, tt.password, "10.0.0.1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Login error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			claims, err := verifier.Verify(context.Background(), token)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if claims["user_id"] != float64(7) || claims["role"] != string(auth.RoleEditor) {
+				t.Errorf("claims = %v, want user_id 7 and role editor", claims)
+			}
+		})
+	}
+}
+
+func TestUserServiceThrottlesLogins(t *testing.T) {
+	tests := []struct {
+		name    string
+		logins  []string
+		ips     []string
+		wantErr []bool
+	}{
+		{
+			name:    "per login",
+			logins:  []string{"editor", "editor", "editor", "nobody"},
+			ips:     []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"},
+			wantErr: []bool{false, false, true, false},
+		},
+		{
+			name:    "per address",
+			logins:  []string{"a", "b", "c", "d", "e"},
+			ips:     []string{"10.0.0.1", "10.0.0.1", "10.0.0.1", "10.0.0.1", "10.0.0.2"},
+			wantErr: []bool{false, false, false, true, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestUserService(t, config.AuthConfig{LoginAttemptsPerMinute: 2, LoginIPAttemptsPerMinute: 3})
+
+			for i, login := range tt.logins {
+				_, err := s.Login(context.Background(), login, "wrong", tt.ips[i])
+				if limited := errors.Is(err, apperrors.ErrRateLimited); limited != tt.wantErr[i] {
+					t.Errorf("attempt %d (%s from %s): rate limited = %v, want %v", i, login, tt.ips[i], limited, tt.wantErr[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package bannerservice
+
+import (
+	"banner-service/internal/models"
+	"banner-service/internal/utils"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const warmupPageSize = 500
+
+type WarmupService struct {
+	bannerService *BannerService
+	dbRepo        DBBannerRepository
+	rate          int
+	logger        *slog.Logger
+
+	mu     sync.Mutex
+	status models.WarmupStatus
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewWarmupService(bannerService *BannerService, dbRepo DBBannerRepository, rate int, logger *slog.Logger) *WarmupService {
+	return &WarmupService{
+		bannerService: bannerService,
+		dbRepo:        dbRepo,
+		rate:          rate,
+		logger:        logger,
+	}
+}
+
+func (s *WarmupService) Start(ctx context.Context) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.status.Running {
+		return false
+	}
+	startedAt := time.Now()
+	s.status = models.WarmupStatus{
+		Running:   true,
+		StartedAt: &startedAt,
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(ctx)
+	}()
+
+	return true
+}
+
+func (s *WarmupService) Stop() {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *WarmupService) Status() models.WarmupStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.status
+}
+
+func (s *WarmupService) run(ctx context.Context) {
+	err := s.warm(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cancel()
+	s.status.Running = false
+	finishedAt := time.Now()
+	s.status.FinishedAt = &finishedAt
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cache warm-up failed", "banners", s.status.Banners, "keys", s.status.Keys, "error", err)
+		s.status.Error = err.Error()
+		return
+	}
+	s.logger.InfoContext(ctx, "cache warm-up finished", "banners", s.status.Banners, "keys", s.status.Keys, "duration", finishedAt.Sub(*s.status.StartedAt))
+}
+
+func (s *WarmupService) warm(ctx context.Context) error {
+	var ticker *time.Ticker
+	if s.rate > 0 {
+		ticker = time.NewTicker(time.Second / time.Duration(s.rate))
+		defer ticker.Stop()
+	}
+
+	afterID := 0
+	loaded := make(map[string]struct{})
+	features := make(map[int]*models.Feature)
+	for {
+		loadedAt := time.Now()
+		banners, err := s.dbRepo.GetActiveBannersAfter(ctx, afterID, warmupPageSize)
+		if err != nil {
+			return err
+		}
+		if len(banners) == 0 {
+			return nil
+		}
+
+		for _, banner := range banners {
+			afterID = banner.BannerID
+
+			feature, ok := features[banner.FeatureID]
+			if !ok {
+				feature = s.bannerService.featureSettings(ctx, banner.FeatureID)
+				features[banner.FeatureID] = feature
+			}
+			ttl, cacheable := s.bannerService.cacheTTL(feature)
+			if !cacheable {
+				continue
+			}
+
+			for _, tagID := range banner.TagIDs {
+				key := utils.MakeCacheKey(banner.FeatureID, tagID)
+				if _, ok := loaded[key]; ok {
+					continue
+				}
+
+				if ticker != nil {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-ticker.C:
+					}
+				}
+
+				if feature.RotationMode != models.RotationModeNone {
+					loaded[key] = struct{}{}
+					if _, err := s.bannerService.loadBanner(ctx, key, banner.FeatureID, tagID); err != nil {
+						return err
+					}
+				} else {
+					entry := &models.BannerCacheEntry{Banners: []*models.Banner{banner}, RotationMode: feature.RotationMode}
+					if err := s.bannerService.storeBanner(ctx, key, entry, ttl, loadedAt); err != nil {
+						return err
+					}
+				}
+
+				s.mu.Lock()
+				s.status.Keys++
+				s.mu.Unlock()
+			}
+
+			s.mu.Lock()
+			s.status.Banners++
+			s.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var pgxTracer = otel.Tracer("banner-service/postgres")
+
+type PgxPool struct {
+	pool *pgxpool.Pool
+}
+
+func NewPgxPool(pool *pgxpool.Pool) *PgxPool {
+	return &PgxPool{
+		pool: pool,
+	}
+}
+
+func (p *PgxPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgxTx{Tx: tx}, nil
+}
+
+func (p *PgxPool) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return tracedExec(ctx, p.pool.Exec, sql, args...)
+}
+
+func (p *PgxPool) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return tracedQuery(ctx, p.pool.Query, sql, args...)
+}
+
+func (p *PgxPool) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return tracedQueryRow(ctx, p.pool.QueryRow, sql, args...)
+}
+
+type pgxTx struct {
+	pgx.Tx
+}
+
+func (tx pgxTx) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return tracedExec(ctx, tx.Tx.Exec, sql, args...)
+}
+
+func (tx pgxTx) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return tracedQuery(ctx, tx.Tx.Query, sql, args...)
+}
+
+func (tx pgxTx) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return tracedQueryRow(ctx, tx.Tx.QueryRow, sql, args...)
+}
+
+func (tx pgxTx) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	ctx, span := pgxTracer.Start(ctx, "postgres Batch",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, attribute.Int("db.operation.batch.size", b.Len())),
+	)
+
+	return &tracedBatchResults{BatchResults: tx.Tx.SendBatch(ctx, b), span: span}
+}
+
+func tracedExec(ctx context.Context, exec func(context.Context, string, ...interface{}) (pgconn.CommandTag, error), sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	ctx, span := startQuerySpan(ctx, "Exec", sql)
+	cmdTag, err := exec(ctx, sql, args...)
+	endQuerySpan(span, err)
+
+	return cmdTag, err
+}
+
+func tracedQuery(ctx context.Context, query func(context.Context, string, ...interface{}) (pgx.Rows, error), sql string, args ...interface{}) (pgx.Rows, error) {
+	ctx, span := startQuerySpan(ctx, "Query", sql)
+	rows, err := query(ctx, sql, args...)
+	if err != nil {
+		endQuerySpan(span, err)
+		return nil, err
+	}
+
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+func tracedQueryRow(ctx context.Context, queryRow func(context.Context, string, ...interface{}) pgx.Row, sql string, args ...interface{}) pgx.Row {
+	ctx, span := startQuerySpan(ctx, "QueryRow", sql)
+
+	return &tracedRow{row: queryRow(ctx, sql, args...), span: span}
+}
+
+func startQuerySpan(ctx context.Context, operation, sql string) (context.Context, trace.Span) {
+	return pgxTracer.Start(ctx, "postgres "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBQueryText(sql)),
+	)
+}
+
+func endQuerySpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+type tracedRows struct {
+	pgx.Rows
+	span   trace.Span
+	closed bool
+}
+
+func (r *tracedRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.Close()
+
+	return false
+}
+
+func (r *tracedRows) Close() {
+	r.Rows.Close()
+	if !r.closed {
+		r.closed = true
+		endQuerySpan(r.span, r.Rows.Err())
+	}
+}
+
+type tracedRow struct {
+	row  pgx.Row
+	span trace.Span
+}
+
+func (r *tracedRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	endQuerySpan(r.span, err)
+
+	return err
+}
+
+type tracedBatchResults struct {
+	pgx.BatchResults
+	span trace.Span
+}
+
+func (b *tracedBatchResults) Close() error {
+	err := b.BatchResults.Close()
+	endQuerySpan(b.span, err)
+
+	return err
+}
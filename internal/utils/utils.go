@@ -1,8 +1,19 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
+
+	"github.com/jackc/pgconn"
+)
+
+const (
+	foreignKeyViolationCode = "23503"
+	uniqueViolationCode     = "23505"
+	raiseExceptionCode      = "P0001"
+
+	uniqueCombinationMessage = "Not a unique combination of tag_id and feature_id"
 )
 
 func ParsePositiveInt(s string) (int, error) {
@@ -19,3 +30,26 @@ func ParsePositiveInt(s string) (int, error) {
 func MakeCacheKey(featureID, tagID int) string {
 	return fmt.Sprintf("feature%d-tag%d", featureID, tagID)
 }
+
+func IsForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode
+}
+
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
+
+func IsUniqueCombinationViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == raiseExceptionCode && pgErr.Message == uniqueCombinationMessage
+}
+
+func ViolatedTable(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.TableName
+	}
+	return ""
+}